@@ -11,6 +11,7 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"os/signal"
 	"path/filepath"
 	"strconv"
 	"strings"
@@ -21,6 +22,9 @@ import (
 
 const SocketPath = "/tmp/ewwd.sock"
 
+// stateFile is the one-shot handoff `ewwd rebuild` writes for the new binary.
+const stateFile = "/tmp/ewwd-state.json"
+
 // importSystemdEnv backfills env vars (WAYLAND_DISPLAY et al.) from the systemd user environment.
 func importSystemdEnv() {
 	out, err := exec.Command("systemctl", "--user", "show-environment").Output()
@@ -53,6 +57,7 @@ type Daemon struct {
 	desiredOpen bool
 	openPending bool
 	ewwDone     chan error
+	restartCh   chan struct{}
 }
 
 func New(autoOpen bool) (*Daemon, error) {
@@ -61,11 +66,12 @@ func New(autoOpen bool) (*Daemon, error) {
 	ctx, cancel := context.WithCancel(context.Background())
 	state := NewState()
 	d := &Daemon{
-		state:    state,
-		ctx:      ctx,
-		cancel:   cancel,
-		config:   cfg,
-		autoOpen: autoOpen,
+		state:     state,
+		ctx:       ctx,
+		cancel:    cancel,
+		config:    cfg,
+		autoOpen:  autoOpen,
+		restartCh: make(chan struct{}, 1),
 	}
 
 	d.server = daemon.NewServer(SocketPath, d.handleCommand)
@@ -84,6 +90,7 @@ func (d *Daemon) Run() error {
 	fmt.Printf("ewwd: listening on %s\n", SocketPath)
 
 	d.initProviders()
+	d.restoreState()
 	for _, p := range d.providers {
 		go func(p providers.Provider) {
 			notify := func(data any) {
@@ -106,8 +113,19 @@ func (d *Daemon) Run() error {
 		}()
 	}
 
-	sig := d.server.WaitForSignal()
-	fmt.Printf("\newwd: received %s, shutting down\n", sig)
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(sigCh)
+
+	restart := false
+	select {
+	case sig := <-sigCh:
+		fmt.Printf("\newwd: received %s, shutting down\n", sig)
+	case <-d.restartCh:
+		time.Sleep(50 * time.Millisecond)
+		fmt.Println("ewwd: restarting...")
+		restart = true
+	}
 	d.cancel()
 	d.server.Shutdown()
 
@@ -115,9 +133,94 @@ func (d *Daemon) Run() error {
 		p.Stop()
 	}
 
+	if restart {
+		return syscall.Exec(newUpdater().BinPath(), os.Args, os.Environ())
+	}
 	return nil
 }
 
+// newUpdater describes how `ewwd rebuild` replaces ~/.local/bin/ewwd. Most providers re-read
+// their sources on start; the state file carries what only lives in memory, like timers.
+func newUpdater() *daemon.Updater {
+	return &daemon.Updater{
+		Name:      "ewwd",
+		Package:   "./cmd/ewwd",
+		Socket:    SocketPath,
+		Unit:      "ewwd.service",
+		StateFile: stateFile,
+	}
+}
+
+// handleRebuild builds and installs a new ewwd, then execs it in place; eww windows stay
+// open since eww itself keeps running. A watchdog rolls back if the new binary fails.
+//
+// Provider state is written to stateFile before the binary swap and consumed once by
+// restoreState after exec.
+func (d *Daemon) handleRebuild() string {
+	u := newUpdater()
+	stamp, err := u.Build()
+	if err != nil {
+		return fmt.Sprintf("error: %v", err)
+	}
+	if err := d.saveState(); err != nil {
+		os.Remove(u.BinPath() + ".new")
+		return fmt.Sprintf("error: state write: %v", err)
+	}
+	if err := u.Install(stamp); err != nil {
+		os.Remove(stateFile)
+		return fmt.Sprintf("error: %v", err)
+	}
+	select {
+	case d.restartCh <- struct{}{}:
+	default:
+	}
+	return "rebuilt: restarting..."
+}
+
+// saveState writes every StatefulProvider's state to stateFile, keyed by provider name.
+func (d *Daemon) saveState() error {
+	saved := make(map[string]json.RawMessage)
+	for _, p := range d.providers {
+		sp, ok := p.(providers.StatefulProvider)
+		if !ok {
+			continue
+		}
+		data, err := sp.SaveState()
+		if err != nil {
+			return fmt.Errorf("%s: %w", p.Name(), err)
+		}
+		saved[p.Name()] = data
+	}
+	data, err := json.Marshal(saved)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(stateFile, data, 0o600)
+}
+
+// restoreState hands a rebuild's saved state back to its providers before they start.
+func (d *Daemon) restoreState() {
+	data, err := os.ReadFile(stateFile)
+	if err != nil {
+		return
+	}
+	os.Remove(stateFile)
+	var saved map[string]json.RawMessage
+	if err := json.Unmarshal(data, &saved); err != nil {
+		fmt.Fprintf(os.Stderr, "ewwd: state restore: %v\n", err)
+		return
+	}
+	for _, p := range d.providers {
+		sp, ok := p.(providers.StatefulProvider)
+		if raw, found := saved[p.Name()]; ok && found {
+			if err := sp.RestoreState(raw); err != nil {
+				fmt.Fprintf(os.Stderr, "ewwd: state restore: %s: %v\n", p.Name(), err)
+			}
+		}
+	}
+	fmt.Println("ewwd: state restored")
+}
+
 func (d *Daemon) initProviders() {
 	cfg := d.config.Eww
 	d.providers = []providers.Provider{
@@ -149,6 +252,10 @@ func (d *Daemon) handleCommand(command string) string {
 		return "running"
 	case "ping":
 		return "pong"
+	case "version":
+		return daemon.ReadBuildInfo().JSON()
+	case "rebuild":
+		return d.handleRebuild()
 	case "state":
 		data, err := d.state.JSON()
 		if err != nil {
@@ -10,6 +10,7 @@ package main
 
 import (
 	"dotfiles/cmds/internal/daemon"
+	"encoding/json"
 	"fmt"
 	"os"
 	"strings"
@@ -43,6 +44,10 @@ func main() {
 		cmdSubscribe()
 	case "action":
 		cmdAction()
+	case "rebuild":
+		cmdRebuild()
+	case "version", "--version":
+		cmdVersion()
 	case "help", "-h", "--help":
 		cmdHelp()
 	default:
@@ -198,6 +203,41 @@ func cmdAction() {
 	}
 }
 
+// cmdRebuild asks the daemon to rebuild itself; `--watchdog <stamp>` is the verifier the old
+// binary starts after installing the new one.
+func cmdRebuild() {
+	if len(os.Args) == 4 && os.Args[2] == "--watchdog" {
+		if err := newUpdater().Watch(os.Args[3]); err != nil {
+			fmt.Fprintf(os.Stderr, "ewwd rebuild: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+	cmdOpen("rebuild")
+}
+
+// cmdVersion prints this binary's build info and, when it differs, the running daemon's.
+func cmdVersion() {
+	local := daemon.ReadBuildInfo()
+	fmt.Println("ewwd " + local.String())
+	if !client.IsRunning() {
+		return
+	}
+	resp, err := client.Send("version")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+	var running daemon.BuildInfo
+	if err := json.Unmarshal([]byte(resp), &running); err != nil {
+		fmt.Println("daemon: " + resp)
+		return
+	}
+	if running.Stamp != local.Stamp || running.String() != local.String() {
+		fmt.Println("daemon: " + running.String())
+	}
+}
+
 func cmdHelp() {
 	fmt.Println(`ewwd — System utilities daemon for eww
 
@@ -209,6 +249,8 @@ Usage:
   ewwd close            Mark widgets closed and run eww close-all
   ewwd status           Check if daemon is running
   ewwd status --json    Return full state as JSON
+  ewwd rebuild          Rebuild binary and restart in place (rolls back if it fails to start)
+  ewwd version          Show build info (commit, dirty flag, build time) for the binary and daemon
 
 Query/Subscribe (for eww):
   ewwd query [topic]    Get state (network|date|audio|bluetooth|music|timer|weather|...)
@@ -1,24 +1,43 @@
 package main
 
 import (
+	"cmp"
+	"dotfiles/cmds/internal/config"
 	"dotfiles/cmds/internal/hyprd/hypr"
+	"dotfiles/cmds/internal/hyprd/session"
+	"dotfiles/cmds/internal/hyprd/state"
+	"errors"
 	"fmt"
+	"image"
+	"os"
+	"path/filepath"
 	"strings"
 	"sync"
 )
 
 const (
-	defaultActiveBorder = "rgba(f2a170ff)"
-	defaultActiveShadow = "rgba(f2a17008)"
-	accentShadowAlpha   = "08"
+	defaultAccent     = "f2a170" // used when theme.yaml has no accent role
+	accentShadowAlpha = "08"
 )
 
+const accentUsage = "usage: accent <#rrggbb|rrggbb|reset> | accent auto [workspaces|off]"
+
 type Accent struct {
-	hypr *hypr.Client
+	hypr  *hypr.Client
+	state *state.State
+
+	mu       sync.Mutex
+	base     string // theme accent, shown when nothing else is set
+	color    string
+	override string // share-mode border; wins over color until cleared
+	current  accentTarget
 
-	mu      sync.Mutex
-	color   string
-	current accentTarget
+	// Auto mode: palette from the wallpaper in autoFile, used when no manual color is
+	// set; perWorkspace picks palette[(ws-1) % len] instead of the first entry.
+	auto         bool
+	perWorkspace bool
+	autoFile     string
+	palette      []string
 }
 
 type accentTarget struct {
@@ -26,12 +45,40 @@ type accentTarget struct {
 	Shadow string
 }
 
-func NewAccent(hypr *hypr.Client) *Accent {
+func NewAccent(hypr *hypr.Client, s *state.State) *Accent {
 	return &Accent{
-		hypr: hypr,
+		hypr:  hypr,
+		state: s,
+		base:  themeAccent(),
 	}
 }
 
+// themeAccent returns the active palette's accent role as rrggbb, or defaultAccent.
+func themeAccent() string {
+	_, palette, err := config.ActivePalette()
+	if err == nil {
+		var accent string
+		if accent, err = palette.Resolve("accent"); err == nil {
+			return accent[:6]
+		}
+	}
+	if !errors.Is(err, os.ErrNotExist) {
+		fmt.Fprintf(os.Stderr, "hyprd accent: theme: %v\n", err)
+	}
+	return defaultAccent
+}
+
+// ReloadTheme re-reads the theme accent and repaints; the cached target is dropped because
+// `hyprctl reload` resets the border behind hyprd's back.
+func (a *Accent) ReloadTheme() error {
+	base := themeAccent()
+	a.mu.Lock()
+	a.base = base
+	a.current = accentTarget{}
+	a.mu.Unlock()
+	return a.Apply()
+}
+
 func (a *Accent) Invalidate() {
 	a.mu.Lock()
 	a.current = accentTarget{}
@@ -40,8 +87,11 @@ func (a *Accent) Invalidate() {
 
 func (a *Accent) Execute(arg string) (string, error) {
 	fields := strings.Fields(arg)
+	if len(fields) > 0 && fields[0] == "auto" {
+		return a.setAuto(fields[1:])
+	}
 	if len(fields) != 1 {
-		return "", fmt.Errorf("usage: accent <#rrggbb|rrggbb|reset>")
+		return "", errors.New(accentUsage)
 	}
 
 	color, clear, err := parseAccentColor(fields[0])
@@ -70,6 +120,22 @@ func (a *Accent) Reset() error {
 	return a.Apply()
 }
 
+// Override pins the border to color regardless of `accent` calls; "" releases it.
+func (a *Accent) Override(color string) error {
+	parsed, clear, err := parseAccentColor(color)
+	if err != nil {
+		return err
+	}
+	a.mu.Lock()
+	if clear {
+		a.override = ""
+	} else {
+		a.override = parsed
+	}
+	a.mu.Unlock()
+	return a.Apply()
+}
+
 func parseAccentColor(raw string) (string, bool, error) {
 	value := strings.TrimSpace(strings.ToLower(raw))
 	if value == "" || value == "reset" || value == "clear" || value == "default" {
@@ -89,15 +155,12 @@ func parseAccentColor(raw string) (string, bool, error) {
 }
 
 func (a *Accent) Apply() error {
-	target := accentTarget{Border: defaultActiveBorder, Shadow: defaultActiveShadow}
 	a.mu.Lock()
-	color := a.color
+	color := cmp.Or(a.override, a.color, a.autoColor(), a.base, defaultAccent)
 	a.mu.Unlock()
-	if color != "" {
-		target = accentTarget{
-			Border: "rgba(" + color + "ff)",
-			Shadow: "rgba(" + color + accentShadowAlpha + ")",
-		}
+	target := accentTarget{
+		Border: "rgba(" + color + "ff)",
+		Shadow: "rgba(" + color + accentShadowAlpha + ")",
 	}
 
 	a.mu.Lock()
@@ -116,3 +179,112 @@ func (a *Accent) Apply() error {
 	a.mu.Unlock()
 	return nil
 }
+
+// autoColor returns the auto-mode accent for the current workspace, or "". Callers hold mu.
+func (a *Accent) autoColor() string {
+	if !a.auto || len(a.palette) == 0 {
+		return ""
+	}
+	if !a.perWorkspace || a.state == nil {
+		return a.palette[0]
+	}
+	ws := max(a.state.GetWorkspace(), 1)
+	return a.palette[(ws-1)%len(a.palette)]
+}
+
+// setAuto handles `accent auto [workspaces|off]`.
+func (a *Accent) setAuto(args []string) (string, error) {
+	perWorkspace := false
+	switch {
+	case len(args) == 0:
+	case len(args) == 1 && args[0] == "workspaces":
+		perWorkspace = true
+	case len(args) == 1 && args[0] == "off":
+		a.mu.Lock()
+		a.auto, a.perWorkspace, a.autoFile, a.palette = false, false, "", nil
+		a.mu.Unlock()
+		return "accent: auto off", a.Apply()
+	default:
+		return "", errors.New(accentUsage)
+	}
+
+	file, palette, err := a.wallpaperPalette()
+	if err != nil {
+		return "", err
+	}
+	a.mu.Lock()
+	a.auto, a.perWorkspace, a.autoFile, a.palette = true, perWorkspace, file, palette
+	a.mu.Unlock()
+	if err := a.Apply(); err != nil {
+		return "", err
+	}
+	mode := ""
+	if perWorkspace {
+		mode = ", per workspace"
+	}
+	return fmt.Sprintf("accent: auto from %s%s: #%s", filepath.Base(file), mode, strings.Join(palette, " #")), nil
+}
+
+// Refresh re-derives the auto palette when the wallpaper has changed since it was taken.
+func (a *Accent) Refresh() error {
+	a.mu.Lock()
+	auto, file := a.auto, a.autoFile
+	a.mu.Unlock()
+	if !auto || a.state == nil || session.NewBG(a.state).WallpaperPath() == file {
+		return nil
+	}
+	file, palette, err := a.wallpaperPalette()
+	if err != nil {
+		return err
+	}
+	a.mu.Lock()
+	a.autoFile, a.palette = file, palette
+	a.mu.Unlock()
+	return a.Apply()
+}
+
+// wallpaperPalette returns the current wallpaper and its palette, from the cache when
+// the file is unchanged, else from a freshly grabbed frame.
+func (a *Accent) wallpaperPalette() (string, []string, error) {
+	if a.state == nil {
+		return "", nil, errors.New("accent auto: no daemon state")
+	}
+	bg := session.NewBG(a.state)
+	file := bg.WallpaperPath()
+	key, err := paletteCacheKey(file)
+	if err != nil {
+		return "", nil, fmt.Errorf("accent auto: %w", err)
+	}
+	cache := loadPaletteCache()
+	if palette := cache[key]; len(palette) > 0 {
+		return file, palette, nil
+	}
+
+	frame, err := os.CreateTemp("", "hyprd-frame-*.png")
+	if err != nil {
+		return "", nil, err
+	}
+	frame.Close()
+	defer os.Remove(frame.Name())
+	if err := bg.Frame(frame.Name()); err != nil {
+		return "", nil, fmt.Errorf("accent auto: %w", err)
+	}
+	f, err := os.Open(frame.Name())
+	if err != nil {
+		return "", nil, err
+	}
+	img, _, err := image.Decode(f)
+	f.Close()
+	if err != nil {
+		return "", nil, fmt.Errorf("accent auto: decode frame: %w", err)
+	}
+	palette := extractPalette(img)
+	if len(palette) == 0 {
+		return "", nil, fmt.Errorf("accent auto: no colours in frame of %s", filepath.Base(file))
+	}
+	cache[key] = palette
+	if err := cache.save(); err != nil {
+		fmt.Fprintf(os.Stderr, "hyprd accent: save palette cache: %v\n", err)
+	}
+	return file, palette, nil
+}
@@ -3,7 +3,9 @@ package main
 import (
 	"dotfiles/cmds/internal/config"
 	"dotfiles/cmds/internal/daemon"
+	"dotfiles/cmds/internal/hyprd/activity"
 	"dotfiles/cmds/internal/hyprd/browser"
+	"dotfiles/cmds/internal/hyprd/hooks"
 	"dotfiles/cmds/internal/hyprd/hypr"
 	"dotfiles/cmds/internal/hyprd/kitty"
 	notifypkg "dotfiles/cmds/internal/hyprd/notify"
@@ -14,7 +16,6 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
-	"os/exec"
 	"os/signal"
 	"path/filepath"
 	"strconv"
@@ -43,9 +44,13 @@ type Daemon struct {
 	server    *daemon.Server
 	config    atomic.Pointer[config.HyprConfig]
 	lockCtl   *session.Lock
+	idleCtl   *session.Idle
 	shareCtl  *session.Share
 	pickerCtl *session.Picker
 	accentCtl *Accent
+	activity  *activity.Recorder
+	hooks     *hooks.Runner
+	lastBoot  atomic.Pointer[session.BootTimeline]
 	restartCh chan struct{}
 }
 
@@ -64,10 +69,25 @@ func New() (*Daemon, error) {
 		state:     stateStore,
 		lockCtl:   session.NewLock(hyprClient, stateStore),
 		pickerCtl: session.NewPicker(hyprClient, stateStore),
-		accentCtl: NewAccent(hyprClient),
+		accentCtl: NewAccent(hyprClient, stateStore),
+		activity:  activity.NewRecorder(),
+		hooks:     hooks.NewRunner(stateStore),
 		restartCh: make(chan struct{}, 1),
 	}
 	d.config.Store(&cfg)
+	d.idleCtl = session.NewIdle(hyprClient, stateStore, d.lockCtl)
+	d.lockCtl.SetPublish(func(event session.LockEvent) {
+		if err := session.AppendLockEvent(event); err != nil {
+			fmt.Fprintf(os.Stderr, "hyprd: lock history: %v\n", err)
+		}
+		d.presenceChanged()
+		d.fireLockHook(event)
+	})
+	d.lockCtl.SetDispatch(d.handleCommand)
+	d.idleCtl.SetPublish(func(bool) { d.presenceChanged() })
+	if err := d.activity.Configure(cfg.Activity); err != nil {
+		fmt.Fprintf(os.Stderr, "hyprd: activity: %v\n", err)
+	}
 	d.shareCtl = session.NewShare(hyprClient, stateStore, func() config.GapsOutConfig {
 		cfg := d.config.Load()
 		if cfg == nil {
@@ -75,6 +95,22 @@ func New() (*Daemon, error) {
 		}
 		return cfg.Windows.GapsOut
 	})
+	d.shareCtl.SetAccent(d.accentCtl.Override)
+	d.shareCtl.SetPublish(func(active bool) {
+		if active {
+			d.hooks.Fire(hooks.Event{Name: "share_on"})
+		} else {
+			d.hooks.Fire(hooks.Event{Name: "share_off"})
+		}
+	})
+	session.SetSessionPublish(func(s config.Session) {
+		d.hooks.Fire(hooks.Event{Name: "session_open", Workspace: s.Workspace, Session: s.Name})
+	})
+	notifypkg.SetAgentPublish(func(list []notifypkg.AgentSession) {
+		if d.server != nil && d.server.Subs != nil {
+			d.server.Subs.Notify("agents", list)
+		}
+	})
 
 	d.server = daemon.NewServer(SocketPath, d.handleCommand)
 	d.server.OnSubscribe = d.sendInitialState
@@ -95,7 +131,7 @@ func (d *Daemon) Run() error {
 	}
 	fmt.Printf("hyprd: listening on %s\n", SocketPath)
 
-	events := NewEventLoop(d.hypr, d.state, d.server.Subs, d.accentCtl, d.server.Done())
+	events := NewEventLoop(d.hypr, d.state, d.server.Subs, d.accentCtl, d.activity, d.hooks, d.server.Done())
 	go func() {
 		if err := events.Run(); err != nil {
 			fmt.Fprintf(os.Stderr, "hyprd: event loop error: %v\n", err)
@@ -103,6 +139,9 @@ func (d *Daemon) Run() error {
 	}()
 
 	go d.watchConfig(d.server.Done())
+	go d.scheduleBackground(d.server.Done())
+	go d.activity.Run(d.server.Done())
+	defer d.activity.Close()
 
 	sigCh := make(chan os.Signal, 1)
 	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
@@ -117,6 +156,7 @@ func (d *Daemon) Run() error {
 		time.Sleep(50 * time.Millisecond)
 		fmt.Println("hyprd: restarting...")
 		d.server.Shutdown()
+		d.activity.Close() // exec skips deferred calls
 		return d.execSelf()
 	}
 }
@@ -130,6 +170,20 @@ func (d *Daemon) sendInitialState(sub *daemon.Subscriber, topics []string) {
 	if sub.WantsTopic("split") {
 		sub.SendEvent("split", d.state.GetSplitRatio())
 	}
+
+	if sub.WantsTopic("presence") {
+		sub.SendEvent("presence", d.presence())
+	}
+
+	if sub.WantsTopic("agents") {
+		sub.SendEvent("agents", notifypkg.AgentSessions())
+	}
+
+	if sub.WantsTopic("init") {
+		if boot := d.lastBoot.Load(); boot != nil {
+			sub.SendEvent("init", boot)
+		}
+	}
 }
 
 // handleCommand routes one line from the daemon socket: `<verb> [raw args]`.
@@ -139,9 +193,14 @@ func (d *Daemon) handleCommand(command string) string {
 
 	switch cmd {
 	case "status":
+		if arg == "--json" {
+			return d.statusJSON()
+		}
 		return "running"
 	case "ping":
 		return "pong"
+	case "version":
+		return daemon.ReadBuildInfo().JSON()
 	case "state":
 		data, err := d.state.JSON()
 		if err != nil {
@@ -149,11 +208,14 @@ func (d *Daemon) handleCommand(command string) string {
 		}
 		return string(data)
 	case "bg":
-		bg := session.NewBG(&d.config.Load().Background)
+		bg := session.NewBG(d.state)
 		result, err := bg.Execute(arg)
 		if err != nil {
 			return fmt.Sprintf("error: %v", err)
 		}
+		if err := d.accentCtl.Refresh(); err != nil {
+			result += "\naccent: " + err.Error()
+		}
 		return result
 	case "split":
 		split := wm.NewSplit(d.hypr, d.state)
@@ -176,6 +238,13 @@ func (d *Daemon) handleCommand(command string) string {
 			return fmt.Sprintf("error: %v", err)
 		}
 		return result
+	case "pip":
+		pip := wm.NewPiP(d.hypr, d.state)
+		result, err := pip.Execute(arg)
+		if err != nil {
+			return fmt.Sprintf("error: %v", err)
+		}
+		return result
 	case "swap":
 		monocle := wm.NewMonocle(d.hypr, d.state)
 		if _, err := monocle.DeactivateIfActive(); err != nil {
@@ -251,7 +320,18 @@ func (d *Daemon) handleCommand(command string) string {
 	case "shadow":
 		return d.handleShadow(arg)
 	case "init":
+		if arg == "--status" {
+			if boot := d.lastBoot.Load(); boot != nil {
+				return boot.String()
+			}
+			return "no boot recorded"
+		}
 		init := d.newInit()
+		if name, ok := strings.CutPrefix(arg, "--profile "); ok {
+			init.SetProfile(strings.TrimSpace(name))
+		} else if arg != "" {
+			return "error: usage: init [--status | --profile <name>]"
+		}
 		result, err := init.Execute()
 		if err != nil {
 			return fmt.Sprintf("error: %v", err)
@@ -263,6 +343,12 @@ func (d *Daemon) handleCommand(command string) string {
 			return fmt.Sprintf("error: %v", err)
 		}
 		return result
+	case "idle":
+		result, err := d.idleCtl.Execute(arg)
+		if err != nil {
+			return fmt.Sprintf("error: %v", err)
+		}
+		return result
 	case "share":
 		result, err := d.shareCtl.Execute(arg)
 		if err != nil {
@@ -288,14 +374,20 @@ func (d *Daemon) handleCommand(command string) string {
 		return d.handleProject(arg)
 	case "notify":
 		return d.handleNotify(arg)
+	case "agents":
+		return d.handleAgents(arg)
 	case "accent":
 		result, err := d.accentCtl.Execute(arg)
 		if err != nil {
 			return fmt.Sprintf("error: %v", err)
 		}
 		return result
+	case "theme":
+		return d.handleTheme(arg)
 	case "rebuild":
 		return d.handleRebuild()
+	case "dry-run":
+		return d.handleDryRun(arg)
 	default:
 		return fmt.Sprintf("unknown command: %s", cmd)
 	}
@@ -344,7 +436,8 @@ func (d *Daemon) handleThreeBody(arg string) string {
 	if name == "" {
 		return "usage: three-body {editor|agents|browser|shadow}"
 	}
-	if name == "agents" {
+	// The notification keybind shares agents' key; a dry run can't know whether one is up.
+	if name == "agents" && !d.hypr.Skip("notify", "activate the displayed notification, if any") {
 		notifier := notifypkg.NewNotifier(d.hypr, d.state, d.config.Load())
 		result, handled, err := notifier.ActivateDisplayed()
 		if err != nil {
@@ -396,45 +489,80 @@ func (d *Daemon) handleNotify(arg string) string {
 	return "ok"
 }
 
+// handleAgents serves the OpenCode jump list: `list [--json]`, `jump <n>`, `next-waiting`.
+func (d *Daemon) handleAgents(arg string) string {
+	fields := strings.Fields(arg)
+	if len(fields) == 0 {
+		fields = []string{"list"}
+	}
+	notifier := notifypkg.NewNotifier(d.hypr, d.state, d.config.Load())
+	var (
+		result string
+		err    error
+	)
+	switch {
+	case fields[0] == "list" && len(fields) == 1:
+		return notifypkg.FormatAgents(notifypkg.AgentSessions())
+	case fields[0] == "list" && len(fields) == 2 && fields[1] == "--json":
+		data, err := json.Marshal(notifypkg.AgentSessions())
+		if err != nil {
+			return fmt.Sprintf("error: %v", err)
+		}
+		return string(data)
+	case fields[0] == "jump" && len(fields) == 2:
+		n, convErr := strconv.Atoi(fields[1])
+		if convErr != nil {
+			return "error: usage: agents jump <n>"
+		}
+		result, err = notifier.JumpAgent(n)
+	case fields[0] == "next-waiting" && len(fields) == 1:
+		result, err = notifier.JumpNextWaiting()
+	default:
+		return "error: usage: agents [list [--json]|jump <n>|next-waiting]"
+	}
+	if err != nil {
+		return fmt.Sprintf("error: %v", err)
+	}
+	return result
+}
+
+// newUpdater describes how `hyprd rebuild` replaces ~/.local/bin/hyprd.
+func newUpdater() *daemon.Updater {
+	return &daemon.Updater{
+		Name:        "hyprd",
+		Package:     "./cmd/hyprd",
+		Socket:      SocketPath,
+		Unit:        "hyprd.service",
+		StateFile:   stateFile,
+		BuildPrefix: []string{"taskset", "-c", computeCPUs},
+	}
+}
+
 // handleRebuild builds ./cmd/hyprd from the dotfiles Go workspace, installs ~/.local/bin/hyprd, and restarts in place.
 //
 // Runtime state is written to stateFile before the binary swap and consumed once by restoreState after exec.
+// The replaced binary stays as hyprd.prev; a watchdog started from it rolls back binary and state
+// if the new daemon does not answer `version` in time (see daemon.Updater).
 func (d *Daemon) handleRebuild() string {
-	home, err := os.UserHomeDir()
+	u := newUpdater()
+	stamp, err := u.Build()
 	if err != nil {
 		return fmt.Sprintf("error: %v", err)
 	}
-	srcDir := filepath.Join(home, "dotfiles", "cmds")
-	if dotfiles := os.Getenv("DOTFILES"); dotfiles != "" {
-		srcDir = filepath.Join(dotfiles, "cmds")
-	}
-	binPath := filepath.Join(home, ".local", "bin", "hyprd")
-	tmpBin := binPath + ".new"
-	if err := os.MkdirAll(filepath.Dir(binPath), 0755); err != nil {
-		return fmt.Sprintf("error: install dir: %v", err)
-	}
-
-	cmd := exec.Command("taskset", "-c", computeCPUs, "go", "build", "-o", tmpBin, "./cmd/hyprd")
-	cmd.Dir = srcDir
-	if out, err := cmd.CombinedOutput(); err != nil {
-		os.Remove(tmpBin)
-		return fmt.Sprintf("error: build failed: %v\n%s", err, out)
-	}
 
 	stateData, err := d.state.JSON()
 	if err != nil {
-		os.Remove(tmpBin)
+		os.Remove(u.BinPath() + ".new")
 		return fmt.Sprintf("error: state dump: %v", err)
 	}
 	if err := os.WriteFile(stateFile, stateData, 0600); err != nil {
-		os.Remove(tmpBin)
+		os.Remove(u.BinPath() + ".new")
 		return fmt.Sprintf("error: state write: %v", err)
 	}
 
-	if err := os.Rename(tmpBin, binPath); err != nil {
-		os.Remove(tmpBin)
+	if err := u.Install(stamp); err != nil {
 		os.Remove(stateFile)
-		return fmt.Sprintf("error: install: %v", err)
+		return fmt.Sprintf("error: %v", err)
 	}
 
 	select {
@@ -463,21 +591,51 @@ func (d *Daemon) execSelf() error {
 	return syscall.Exec(bin, []string{"hyprd"}, os.Environ())
 }
 
+// handleProject serves the project registry (open, list, complete) and the focused
+// workspace's project path (get, set, clear).
 func (d *Daemon) handleProject(arg string) string {
+	sub, val, _ := strings.Cut(strings.TrimSpace(arg), " ")
+	val = strings.TrimSpace(val)
+
+	switch sub {
+	case "open":
+		layout := session.NewLayout(d.hypr, d.state)
+		result, err := layout.OpenProject(strings.Fields(val))
+		if err != nil {
+			return fmt.Sprintf("error: %v", err)
+		}
+		return result
+	case "list":
+		list := session.ProjectStatuses(d.state.GetConfig(), d.state)
+		switch val {
+		case "":
+			return session.FormatProjects(list)
+		case "--json":
+			data, err := json.Marshal(list)
+			if err != nil {
+				return fmt.Sprintf("error: %v", err)
+			}
+			return string(data)
+		default:
+			return "usage: project list [--json]"
+		}
+	case "complete":
+		return strings.Join(session.CompleteProjects(d.state.GetConfig(), val), "\n")
+	}
+
 	wsID, err := d.hypr.ActiveWorkspace()
 	if err != nil {
 		return fmt.Sprintf("error: %v", err)
 	}
-
-	sub, val, _ := strings.Cut(strings.TrimSpace(arg), " ")
-	val = strings.TrimSpace(val)
-
 	switch sub {
 	case "", "get":
 		p := d.state.GetProjectPath(wsID)
 		if p == "" {
 			return fmt.Sprintf("ws%d: (none)", wsID)
 		}
+		if name := session.ProjectAt(d.state.GetConfig(), p); name != "" {
+			return fmt.Sprintf("ws%d: %s (%s)", wsID, p, name)
+		}
 		return fmt.Sprintf("ws%d: %s", wsID, p)
 	case "set":
 		if val == "" {
@@ -489,7 +647,7 @@ func (d *Daemon) handleProject(arg string) string {
 		d.state.SetProjectPath(wsID, "")
 		return fmt.Sprintf("ws%d: cleared", wsID)
 	default:
-		return "usage: project [get|set <path>|clear]"
+		return "usage: project [get|set <path>|clear|open <name>|list [--json]|complete [query]]"
 	}
 }
 
@@ -506,9 +664,34 @@ func (d *Daemon) newInit() *session.Init {
 			Body:    body,
 		})
 	})
+	init.SetPublish(func(timeline session.BootTimeline) {
+		d.lastBoot.Store(&timeline)
+		if d.server != nil && d.server.Subs != nil {
+			d.server.Subs.Notify("init", timeline)
+		}
+	})
 	return init
 }
 
+// handleTheme handles `theme reload`, sent by `dctl theme apply`: notify styles and the
+// accent resolve palette colours at load time, so both are re-read from theme.yaml.
+func (d *Daemon) handleTheme(arg string) string {
+	if arg != "reload" {
+		return "error: usage: theme reload"
+	}
+	cfg := config.LoadHypr()
+	d.state.ReloadConfig(&cfg)
+	d.config.Store(&cfg)
+	if err := d.accentCtl.ReloadTheme(); err != nil {
+		return fmt.Sprintf("error: accent: %v", err)
+	}
+	name, _, err := config.ActivePalette()
+	if err != nil {
+		return fmt.Sprintf("error: %v", err)
+	}
+	return "theme: " + name
+}
+
 // watchConfig hot-reloads ~/dotfiles/cmds/config/hyprd.yaml on change.
 //
 // Watches the parent directory because nvim rename-and-replaces on save, killing file-level watches.
@@ -556,6 +739,9 @@ func (d *Daemon) watchConfig(done <-chan struct{}) {
 				cfg := config.LoadHypr()
 				d.state.ReloadConfig(&cfg)
 				d.config.Store(&cfg)
+				if err := d.activity.Configure(cfg.Activity); err != nil {
+					fmt.Fprintf(os.Stderr, "hyprd: activity: %v\n", err)
+				}
 				fmt.Printf("hyprd: config reloaded\n")
 			})
 		case err, ok := <-watcher.Errors:
@@ -567,6 +753,25 @@ func (d *Daemon) watchConfig(done <-chan struct{}) {
 	}
 }
 
+// scheduleBackground re-evaluates background.playlist schedules once a minute.
+func (d *Daemon) scheduleBackground(done <-chan struct{}) {
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+			if err := session.NewBG(d.state).Apply(); err != nil {
+				fmt.Fprintf(os.Stderr, "hyprd: background schedule: %v\n", err)
+			}
+			if err := d.accentCtl.Refresh(); err != nil {
+				fmt.Fprintf(os.Stderr, "hyprd: auto accent: %v\n", err)
+			}
+		}
+	}
+}
+
 func (d *Daemon) query(topic string) (string, error) {
 	switch topic {
 	case "workspace":
@@ -585,6 +790,14 @@ func (d *Daemon) query(topic string) (string, error) {
 	case "split":
 		return fmt.Sprintf(`"%s"`, d.state.GetSplitRatio()), nil
 
+	case "pip":
+		pip := d.state.GetPiP()
+		if pip == nil {
+			return "null", nil
+		}
+		jsonData, err := json.Marshal(pip)
+		return string(jsonData), err
+
 	case "three-body":
 		allTB := d.state.AllThreeBody()
 		if len(allTB) == 0 {
@@ -593,6 +806,21 @@ func (d *Daemon) query(topic string) (string, error) {
 		jsonData, err := json.Marshal(allTB)
 		return string(jsonData), err
 
+	case "presence":
+		return fmt.Sprintf(`"%s"`, d.presence()), nil
+
+	case "agents":
+		jsonData, err := json.Marshal(notifypkg.AgentSessions())
+		return string(jsonData), err
+
+	case "init":
+		boot := d.lastBoot.Load()
+		if boot == nil {
+			return "null", nil
+		}
+		jsonData, err := json.Marshal(boot)
+		return string(jsonData), err
+
 	case "all", "":
 		jsonData, err := d.state.JSON()
 		return string(jsonData), err
@@ -622,9 +850,60 @@ func joinWorkspaceIDs(ids []int) string {
 	return strings.Join(parts, " ")
 }
 
+// statusJSON is the full state plus a "hyprland" key with the compositor version and the
+// mutation backend the client probed at connect.
+func (d *Daemon) statusJSON() string {
+	data, err := d.state.JSON()
+	if err != nil {
+		return fmt.Sprintf("error: %v", err)
+	}
+	var status map[string]any
+	if err := json.Unmarshal(data, &status); err != nil {
+		return fmt.Sprintf("error: %v", err)
+	}
+	status["hyprland"] = d.hypr.Capabilities()
+	out, err := json.Marshal(status)
+	if err != nil {
+		return fmt.Sprintf("error: %v", err)
+	}
+	return string(out)
+}
+
 func (d *Daemon) notifyWorkspace() {
 	if d.server == nil || d.server.Subs == nil {
 		return
 	}
 	d.server.Subs.Notify("workspace", workspacePayload(d.state))
 }
+
+// presence folds lock state and idle policy into active/away/locked for the `presence` topic.
+func (d *Daemon) presence() string {
+	presence := d.lockCtl.Presence()
+	if presence == session.PresenceActive && d.idleCtl.Active() {
+		return session.PresenceAway
+	}
+	return presence
+}
+
+// presenceChanged pauses activity recording while away or locked, then publishes presence.
+func (d *Daemon) presenceChanged() {
+	d.activity.SetPresent(d.presence() == session.PresenceActive)
+	d.notifyPresence()
+}
+
+// fireLockHook maps lock transitions to hook events: an escalation from pseudo to full fires
+// lock again with kind full.
+func (d *Daemon) fireLockHook(event session.LockEvent) {
+	name := "lock"
+	if event.Event == "unlock" {
+		name = "unlock"
+	}
+	d.hooks.Fire(hooks.Event{Name: name, Time: event.Time, Kind: event.Kind, Trigger: event.Trigger, Seconds: event.Seconds})
+}
+
+func (d *Daemon) notifyPresence() {
+	if d.server == nil || d.server.Subs == nil {
+		return
+	}
+	d.server.Subs.Notify("presence", d.presence())
+}
@@ -0,0 +1,76 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"dotfiles/cmds/internal/hyprd/state"
+)
+
+// dryRunCommands are the window-management verbs `hyprd --dry-run` accepts.
+var dryRunCommands = []string{
+	"split", "hide", "float", "pip", "swap", "ws", "focus", "monocle", "three-body", "shadow", "layout",
+}
+
+// handleDryRun runs a window command against a recording Hyprland client and a scratch copy
+// of State, then reports the ordered dispatches and the state fields it would have changed.
+//
+// Queries still read the live compositor, so steps that depend on an earlier mutation (a
+// window that would have spawned or moved) see the world as it is before the command.
+func (d *Daemon) handleDryRun(command string) string {
+	verb, arg, _ := strings.Cut(command, " ")
+	if !slices.Contains(dryRunCommands, verb) {
+		return fmt.Sprintf("error: dry-run supports: %s", strings.Join(dryRunCommands, ", "))
+	}
+	if sub, _, _ := strings.Cut(strings.TrimSpace(arg), " "); verb == "layout" && (sub == "save" || sub == "new") {
+		return fmt.Sprintf("error: dry-run: layout %s writes config; not supported", sub)
+	}
+
+	before, err := d.state.JSON()
+	if err != nil {
+		return fmt.Sprintf("error: %v", err)
+	}
+	cfg := d.config.Load()
+	scratch := state.NewState(cfg)
+	if err := scratch.Restore(before); err != nil {
+		return fmt.Sprintf("error: copy state: %v", err)
+	}
+	h, rec := d.hypr.DryRun()
+	dry := &Daemon{hypr: h, state: scratch}
+	dry.config.Store(cfg)
+
+	result := dry.handleCommand(command)
+	after, err := scratch.JSON()
+	if err != nil {
+		return fmt.Sprintf("error: %v", err)
+	}
+	changes, err := diffState(before, after)
+	if err != nil {
+		return fmt.Sprintf("error: %v", err)
+	}
+
+	lines := []string{"dry-run: " + command, "result: " + result}
+	ops := rec.Ops()
+	if len(ops) == 0 {
+		lines = append(lines, "dispatches: none")
+	} else {
+		lines = append(lines, "dispatches:")
+		for i, op := range ops {
+			lines = append(lines, fmt.Sprintf("  %d. %s", i+1, op))
+		}
+	}
+	if len(changes) == 0 {
+		lines = append(lines, "state: unchanged")
+	} else {
+		lines = append(lines, "state:")
+		for _, c := range changes {
+			lines = append(lines, "  "+strings.ReplaceAll(c.format("before", "after "), "\n", "\n  "))
+		}
+	}
+	out := strings.Join(lines, "\n")
+	if daemonResponseFailed(result) {
+		return "error: " + out // the command itself failed; keep the partial trace
+	}
+	return out
+}
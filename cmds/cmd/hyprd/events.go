@@ -10,28 +10,42 @@ import (
 	"slices"
 	"strconv"
 	"strings"
+	"sync"
 
 	"dotfiles/cmds/internal/daemon"
+	"dotfiles/cmds/internal/hyprd/activity"
+	"dotfiles/cmds/internal/hyprd/hooks"
 	"dotfiles/cmds/internal/hyprd/hypr"
+	"dotfiles/cmds/internal/hyprd/session"
 	"dotfiles/cmds/internal/hyprd/state"
+	"dotfiles/cmds/internal/hyprd/wm"
 )
 
 // EventLoop mirrors Hyprland's event stream into daemon state and notifies subscribers.
 type EventLoop struct {
-	hypr   *hypr.Client
-	state  *state.State
-	subs   *daemon.SubscriptionManager
-	accent *Accent
-	done   <-chan struct{}
+	hypr     *hypr.Client
+	state    *state.State
+	subs     *daemon.SubscriptionManager
+	accent   *Accent
+	activity *activity.Recorder
+	focus    activity.Focus // active window class/title; workspace fields are filled per observation
+	hooks    *hooks.Runner
+	windows  map[string]hypr.Window // clients as of the last refresh, for closewindow's bare address
+	done     <-chan struct{}
+
+	ancestors func(pid int) map[int]bool // swallow's parent-process lookup; nil reads /proc
+	handlers  sync.WaitGroup             // openwindow handlers running in the background
 }
 
-func NewEventLoop(hypr *hypr.Client, state *state.State, subs *daemon.SubscriptionManager, accent *Accent, done <-chan struct{}) *EventLoop {
+func NewEventLoop(hypr *hypr.Client, state *state.State, subs *daemon.SubscriptionManager, accent *Accent, recorder *activity.Recorder, runner *hooks.Runner, done <-chan struct{}) *EventLoop {
 	return &EventLoop{
-		hypr:   hypr,
-		state:  state,
-		subs:   subs,
-		accent: accent,
-		done:   done,
+		hypr:     hypr,
+		state:    state,
+		subs:     subs,
+		accent:   accent,
+		activity: recorder,
+		hooks:    runner,
+		done:     done,
 	}
 }
 
@@ -91,6 +105,12 @@ func (e *EventLoop) syncState() error {
 		return err
 	}
 	e.resetAccent()
+	if e.activity != nil {
+		if win, err := e.hypr.ActiveWindow(); err == nil && win != nil {
+			e.focus.Class, e.focus.Title = win.Class, win.Title
+		}
+		e.observeFocus()
+	}
 
 	return nil
 }
@@ -102,7 +122,9 @@ func (e *EventLoop) updateOccupied() error {
 	}
 
 	wsSet := make(map[int]bool, len(clients))
+	e.windows = make(map[string]hypr.Window, len(clients))
 	for _, c := range clients {
+		e.windows[c.Address] = c
 		if c.Workspace.ID > 0 {
 			wsSet[c.Workspace.ID] = true
 		}
@@ -126,22 +148,22 @@ func (e *EventLoop) handleEvent(line string) {
 			wsStr = data[:idx]
 		}
 		if ws, err := strconv.Atoi(wsStr); err == nil {
-			e.state.SetWorkspace(ws)
-			e.notifyWorkspace()
-			e.resetAccent()
+			e.switchWorkspace(ws)
 		}
 
 	case "focusedmon":
 		if idx := strings.LastIndex(data, ","); idx >= 0 {
 			if ws, err := strconv.Atoi(data[idx+1:]); err == nil {
-				e.state.SetWorkspace(ws)
-				e.notifyWorkspace()
-				e.resetAccent()
+				e.switchWorkspace(ws)
 			}
 		}
 
 	case "activewindow", "activewindowv2":
 		e.applyAccent()
+		if event == "activewindow" { // CLASS,TITLE; empty when focus leaves every window
+			e.focus.Class, e.focus.Title, _ = strings.Cut(data, ",")
+			e.observeFocus()
+		}
 
 	case "configreloaded":
 		if e.accent != nil {
@@ -150,8 +172,17 @@ func (e *EventLoop) handleEvent(line string) {
 		e.applyAccent()
 
 	case "createworkspace", "destroyworkspace", "openwindow", "movewindow":
+		if event == "openwindow" {
+			if e.state.GetScreenShare() {
+				e.concealOpened(data)
+			}
+			e.swallowOpened(data)
+		}
 		e.updateOccupied()
 		e.notifyWorkspace()
+		if event == "openwindow" {
+			e.fireWindowHook("window_open", data)
+		}
 
 	case "closewindow":
 		addr := data // closewindow emits bare hex (no 0x prefix)
@@ -160,6 +191,8 @@ func (e *EventLoop) handleEvent(line string) {
 		}
 		e.handleThreeBodyClose(addr) // must run before ClearWindowState wipes the entries
 		e.handleMonocleClose(addr)
+		e.handleSwallowClose(addr)
+		e.fireWindowHook("window_close", addr) // before the refresh drops addr from e.windows
 		e.state.ClearWindowState(addr)
 		e.updateOccupied()
 		e.notifyWorkspace()
@@ -167,6 +200,53 @@ func (e *EventLoop) handleEvent(line string) {
 	}
 }
 
+// switchWorkspace records a newly focused workspace and fires the workspace hook when it
+// changed; workspace and focusedmon both report the same switch.
+func (e *EventLoop) switchWorkspace(ws int) {
+	prev := e.state.GetWorkspace()
+	e.state.SetWorkspace(ws)
+	e.notifyWorkspace()
+	e.resetAccent()
+	e.observeFocus()
+	if e.hooks != nil && ws != prev {
+		e.hooks.Fire(hooks.Event{Name: "workspace", Workspace: ws, Previous: prev})
+	}
+}
+
+// fireWindowHook fires a window event for the client at addr, falling back to openwindow's
+// `ADDR,WORKSPACE,CLASS,TITLE` fields for a window the last refresh missed.
+func (e *EventLoop) fireWindowHook(name, data string) {
+	if e.hooks == nil {
+		return
+	}
+	fields := strings.SplitN(data, ",", 4)
+	addr := fields[0]
+	if !strings.HasPrefix(addr, "0x") {
+		addr = "0x" + addr
+	}
+	ev := hooks.Event{Name: name, Address: addr}
+	if w, ok := e.windows[addr]; ok {
+		ev.Workspace, ev.Class, ev.Title = w.Workspace.ID, w.Class, w.Title
+	} else if len(fields) == 4 {
+		ev.Workspace, _ = strconv.Atoi(fields[1])
+		ev.Class, ev.Title = fields[2], fields[3]
+	}
+	e.hooks.Fire(ev)
+}
+
+// observeFocus hands the active window, with its workspace's session and project, to the
+// activity recorder.
+func (e *EventLoop) observeFocus() {
+	if e.activity == nil {
+		return
+	}
+	f := e.focus
+	f.Workspace = e.state.GetWorkspace()
+	f.Session = e.state.GetActiveSession(f.Workspace)
+	f.Project = e.state.GetProjectPath(f.Workspace)
+	e.activity.Observe(f)
+}
+
 func (e *EventLoop) applyAccent() {
 	if e.accent == nil {
 		return
@@ -214,6 +294,40 @@ func (e *EventLoop) handleMonocleClose(addr string) {
 	}
 }
 
+// concealOpened applies the share privacy rules to a window mapped mid-share.
+// openwindow emits `ADDR,WORKSPACE,CLASS,TITLE` with a bare hex address.
+func (e *EventLoop) concealOpened(data string) {
+	addr, _, _ := strings.Cut(data, ",")
+	if !strings.HasPrefix(addr, "0x") {
+		addr = "0x" + addr
+	}
+	e.handlers.Go(func() {
+		if err := session.ConcealOpened(e.hypr, e.state, addr); err != nil {
+			fmt.Fprintf(os.Stderr, "hyprd share: %v\n", err)
+		}
+	})
+}
+
+// swallowOpened hides the terminal a new window was launched from; see wm.Swallow.
+func (e *EventLoop) swallowOpened(data string) {
+	addr, _, _ := strings.Cut(data, ",")
+	if !strings.HasPrefix(addr, "0x") {
+		addr = "0x" + addr
+	}
+	e.handlers.Go(func() {
+		if _, err := wm.NewSwallow(e.hypr, e.state, e.ancestors).Opened(addr); err != nil {
+			fmt.Fprintf(os.Stderr, "hyprd swallow: %v\n", err)
+		}
+	})
+}
+
+// handleSwallowClose brings back the terminal a closing window had swallowed.
+func (e *EventLoop) handleSwallowClose(addr string) {
+	if _, err := wm.NewSwallow(e.hypr, e.state, e.ancestors).Closed(addr); err != nil {
+		fmt.Fprintf(os.Stderr, "hyprd swallow: %v\n", err)
+	}
+}
+
 func (e *EventLoop) notifyWorkspace() {
 	if e.subs == nil {
 		return
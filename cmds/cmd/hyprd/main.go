@@ -5,9 +5,11 @@ import (
 	"dotfiles/cmds/internal/daemon"
 	"dotfiles/cmds/internal/hyprd/cli"
 	notifypkg "dotfiles/cmds/internal/hyprd/notify"
+	"encoding/json"
 	"fmt"
 	"os"
 	"os/exec"
+	"slices"
 	"strings"
 	"syscall"
 	"time"
@@ -15,7 +17,19 @@ import (
 
 var client = daemon.NewClient(SocketPath)
 
+// dryRun is set by a global --dry-run flag; sendCommand then asks the daemon what the
+// command would do instead of running it.
+var dryRun bool
+
 func main() {
+	if i := slices.Index(os.Args, "--dry-run"); i > 0 {
+		dryRun = true
+		os.Args = slices.Delete(os.Args, i, i+1)
+		if len(os.Args) < 2 || !slices.Contains(dryRunCommands, os.Args[1]) {
+			fmt.Fprintf(os.Stderr, "hyprd: --dry-run supports: %s\n", strings.Join(dryRunCommands, ", "))
+			os.Exit(1)
+		}
+	}
 	if len(os.Args) < 2 {
 		runDaemon()
 		return
@@ -36,6 +50,8 @@ func main() {
 		cmdMonocle()
 	case "float":
 		cmdFloat()
+	case "pip":
+		cmdPiP()
 	case "swap":
 		cmdSwap()
 	case "ws":
@@ -64,20 +80,38 @@ func main() {
 		cmdProject()
 	case "lock":
 		cmdLock()
+	case "idle":
+		cmdIdle()
 	case "share":
 		cmdShare()
 	case "notify":
 		cmdNotify()
+	case "agents":
+		cmdAgents()
 	case "accent":
 		cmdAccent()
+	case "theme":
+		cmdTheme()
+	case "config":
+		cli.Config()
 	case "vpn":
 		cli.VPN()
+	case "stats":
+		cli.Stats()
+	case "hooks":
+		cli.Hooks()
 	case "screenshot":
 		cli.Screenshot()
 	case "ssh":
 		cli.SSH()
 	case "rebuild":
 		cmdRebuild()
+	case "version", "--version":
+		cmdVersion()
+	case "record":
+		cmdRecord()
+	case "replay":
+		cmdReplay()
 	case "help", "-h", "--help":
 		cmdHelp()
 	default:
@@ -122,6 +156,9 @@ func sendCommand(cmd string) {
 		fmt.Fprintln(os.Stderr, "hyprd: daemon not running")
 		os.Exit(1)
 	}
+	if dryRun {
+		cmd = "dry-run " + cmd
+	}
 	resp, err := client.Send(cmd)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "error: %v\n", err)
@@ -147,6 +184,19 @@ func requireArg(usage string) string {
 
 // cmdInit imports Wayland env into systemd, starts user services, and then asks the daemon to run init.
 func cmdInit() {
+	if len(os.Args) > 2 && os.Args[2] == "--status" {
+		sendCommand("init --status")
+		return
+	}
+	command := "init"
+	if len(os.Args) > 2 {
+		if os.Args[2] != "--profile" || len(os.Args) != 4 {
+			fmt.Fprintln(os.Stderr, "usage: hyprd init [--status | --profile <name>]")
+			os.Exit(1)
+		}
+		command += " --profile " + os.Args[3]
+	}
+
 	envNames := []string{
 		"PATH", "WAYLAND_DISPLAY", "HYPRLAND_INSTANCE_SIGNATURE",
 		"XDG_CURRENT_DESKTOP", "XDG_SESSION_TYPE", "XDG_SESSION_DESKTOP",
@@ -168,7 +218,7 @@ func cmdInit() {
 		fmt.Fprintln(os.Stderr, "hyprd init: hyprd.service did not become ready; skipping daemon init")
 		os.Exit(1)
 	}
-	sendCommand("init")
+	sendCommand(command)
 }
 
 func runInitCommand(name string, args ...string) {
@@ -193,6 +243,7 @@ func waitHyprdReady(timeout time.Duration) bool {
 func cmdHide()    { sendCommand("hide") }
 func cmdMonocle() { sendCommand("monocle") }
 func cmdFloat()   { sendCommand("float") }
+func cmdPiP()     { sendCommand("pip " + strings.Join(os.Args[2:], " ")) }
 func cmdSwap()    { sendCommand("swap") }
 func cmdSplit()   { sendCommand("split " + strings.Join(os.Args[2:], " ")) }
 func cmdPicker()  { sendCommand("picker " + strings.Join(os.Args[2:], " ")) }
@@ -203,10 +254,14 @@ func cmdBrowser() {
 }
 func cmdProject() { sendCommand("project " + strings.Join(os.Args[2:], " ")) }
 func cmdLock()    { sendCommand("lock " + strings.Join(os.Args[2:], " ")) }
+func cmdIdle()    { sendCommand("idle " + strings.Join(os.Args[2:], " ")) }
 func cmdShare()   { sendCommand("share " + strings.Join(os.Args[2:], " ")) }
 func cmdQuery()   { sendCommand("query " + strings.Join(os.Args[2:], " ")) }
-func cmdBG()      { sendCommand("bg " + requireArg("usage: hyprd bg {ensure|kill}")) }
 func cmdWS()      { sendCommand("ws " + requireArg("usage: hyprd ws <number|up|down>")) }
+func cmdBG() {
+	_ = requireArg("usage: hyprd bg {ensure|kill|next|prev|set <file>|status}")
+	sendCommand("bg " + strings.Join(os.Args[2:], " "))
+}
 func cmdTab() {
 	_ = requireArg("usage: hyprd tab <editor|agents>:<index 0..4>")
 	sendCommand("tab " + strings.Join(os.Args[2:], " "))
@@ -226,9 +281,45 @@ func cmdTabs() {
 	_ = requireArg("usage: hyprd tabs init <profile> <pid> | tabs refresh <position|name|current|all> [pid] | tabs host <alias> [--kitty-pid <pid> --os-window <id>]")
 	sendCommand("tabs " + strings.Join(os.Args[2:], " "))
 }
-func cmdNotify()  { notifypkg.CmdNotify(client, os.Args[2:]) }
-func cmdAccent()  { sendCommand("accent " + strings.Join(os.Args[2:], " ")) }
-func cmdRebuild() { sendCommand("rebuild") }
+func cmdNotify() { notifypkg.CmdNotify(client, os.Args[2:]) }
+func cmdAgents() { sendCommand(strings.TrimSpace("agents " + strings.Join(os.Args[2:], " "))) }
+func cmdAccent() { sendCommand("accent " + strings.Join(os.Args[2:], " ")) }
+func cmdTheme() {
+	_ = requireArg("usage: hyprd theme reload")
+	sendCommand("theme " + strings.Join(os.Args[2:], " "))
+}
+func cmdRebuild() {
+	if len(os.Args) == 4 && os.Args[2] == "--watchdog" {
+		if err := newUpdater().Watch(os.Args[3]); err != nil {
+			fmt.Fprintf(os.Stderr, "hyprd rebuild: %v\n", err)
+			os.Exit(1)
+		}
+		return
+	}
+	sendCommand("rebuild")
+}
+
+// cmdVersion prints this binary's build info and, when it differs, the running daemon's.
+func cmdVersion() {
+	local := daemon.ReadBuildInfo()
+	fmt.Println("hyprd " + local.String())
+	if !client.IsRunning() {
+		return
+	}
+	resp, err := client.Send("version")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "error: %v\n", err)
+		os.Exit(1)
+	}
+	var running daemon.BuildInfo
+	if err := json.Unmarshal([]byte(resp), &running); err != nil {
+		fmt.Println("daemon: " + resp) // predates `version`
+		return
+	}
+	if running.Stamp != local.Stamp || running.String() != local.String() {
+		fmt.Println("daemon: " + running.String())
+	}
+}
 
 func cmdStatus() {
 	jsonOutput := false
@@ -248,7 +339,7 @@ func cmdStatus() {
 	}
 
 	if jsonOutput {
-		resp, err := client.Send("state")
+		resp, err := client.Send("status --json")
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "error: %v\n", err)
 			os.Exit(1)
@@ -283,15 +374,37 @@ func cmdHelp() {
 Usage:
   hyprd                  Start daemon (foreground, auto-inits on fresh session)
   hyprd init             Manually run the boot sequence
+  hyprd init --status    Show the last boot timeline (per-step state and timing)
+  hyprd init --profile <name>
+                         Boot with a named init.profiles entry instead of matching its rules
   hyprd status           Check if daemon is running
-  hyprd status --json    Return full state as JSON
-  hyprd rebuild          Rebuild binary and hot-restart (preserves state)
+  hyprd status --json    Return full state as JSON, plus Hyprland version and mutation backend
+  hyprd rebuild          Rebuild binary and hot-restart (preserves state, rolls back if it fails to start)
+  hyprd version          Show build info (commit, dirty flag, build time) for the binary and daemon
+  hyprd --dry-run <command> ...
+                         Print the dispatches and state changes a window command would make
+  hyprd config check [path]  Validate hyprd.yaml offline (sessions, tab profiles, snapshots)
+  hyprd record <file> [--interval 1s]
+                         Capture the Hyprland event stream and clients snapshots to a trace
+  hyprd replay <file> [--expect <state.json>] [--write <state.json>] [--dispatches]
+                         Replay a trace against a simulated Hyprland and print/diff the state
 
 Window commands:
-  hyprd bg <mode>        Background: code, music, kill, lock, ensure
+  hyprd bg ensure|kill   Spawn the mpvpaper background if dead / kill it
+  hyprd bg next|prev     Pin the next/previous background.playlist entry
+  hyprd bg set <file>    Pin a video from video_path (until the schedule changes)
+  hyprd bg status        Show the current wallpaper and whether it is pinned
+  hyprd accent <#rrggbb|reset>  Set the active border accent (cleared on workspace change)
+  hyprd accent auto [workspaces|off]
+                         Derive accents from the wallpaper frame (optionally one per workspace)
+  hyprd theme reload     Re-read theme.yaml for the default accent and notify styles
   hyprd hide             Toggle hide/show slave (special workspace)
   hyprd monocle          Toggle monocle (isolate focused window)
   hyprd float            Toggle floating (centered at monocle size)
+  hyprd pip [toggle]     Toggle picture-in-picture (float + pin in a corner)
+  hyprd pip corner <nw|ne|se|sw>  Move the pip window to a corner
+  hyprd pip cycle        Move the pip window to the next corner clockwise
+  hyprd pip avoid        Flip pip to the opposite side if the cursor is in its corner
   hyprd swap             Toggle swap between master and slave
   hyprd split            Cycle split ratio (xs → default → lg)
   hyprd split -x|-l      Set specific split ratio
@@ -319,15 +432,37 @@ Sessions:
   hyprd picker open      Open interactive layout picker overlay
   hyprd picker close     Close picker without action
   hyprd picker confirm   Confirm selection (open session on workspace)
+  hyprd picker filter [text]
+                         Fuzzy-rank sessions by name/project/role; no text restores the grid
   hyprd layout --list    List available sessions
   hyprd layout <name>    Open session (loads from ~/dotfiles/cmds/config/hyprd.yaml)
+  hyprd layout save <name> [--workspace N] [--append]
+                         Capture a workspace as a session (drop-in file, or append to hyprd.yaml)
+  hyprd layout close [name|--workspace N] [--snapshot]
+                         Close a session's windows (optionally snapshot its browser first)
+  hyprd layout new <name> --template <t> [--var k=v]... [--workspace N] [--save] [--no-open]
+                         Render a session template and open it (--save writes a drop-in)
+  hyprd project open <name> [--workspace N] [--var k=v]...
+                         Open a registered project on the first free workspace
+  hyprd project list [--json]
+                         List projects with git branch/dirty state and open workspace
+  hyprd project complete [query]
+                         Fuzzy-match project names (for shell completion)
+  hyprd project [get|set <path>|clear]
+                         Show or set the focused workspace's project path
 
 Lock:
   hyprd lock             Pseudo-lock (visual blackout + submap)
   hyprd lock unlock      Exit pseudo-lock (alias: -u)
   hyprd lock full        Full lock (wraps hyprlock with pre/post hooks)
+  hyprd lock history [--since 7d|24h|YYYY-MM-DD]
+                         Daily away time and lock counts from the lock log
+  hyprd idle start       Begin idle stages (from hypridle on-timeout)
+  hyprd idle resume      Cancel pending stages, turn displays back on
+  hyprd idle status      Show idle time, next stage, and active inhibitors
   hyprd share            Toggle screen-share mode
   hyprd share on|off     Enter/exit screen-share mode explicitly
+  hyprd share --dry-run  List windows the share privacy rules would conceal
 
 Browser:
   hyprd browser launch
@@ -339,8 +474,18 @@ Browser:
   hyprd browser restore <name> [--force] [--dry-run]
 
 Query/Subscribe (for eww):
-  hyprd query [topic]    Get state (workspace|hidden|split|three-body|all)
-  hyprd subscribe [...]  Stream events (workspace split)
+  hyprd query [topic]    Get state (workspace|hidden|split|pip|three-body|init|presence|agents|all)
+  hyprd subscribe [...]  Stream events (workspace split presence agents)
+
+Activity (opt-in via activity.enabled):
+  hyprd stats [today|week] [--by project|class|session] [--json]
+                         Focused time per group, excluding away and locked periods
+  hyprd stats export [today|week]
+                         Recorded focus intervals as JSON
+
+Hooks (hooks.events in hyprd.yaml):
+  hyprd hooks test <event> [--class C] [--title T] [--workspace N] [--session S]
+                         Run an event's matching hooks now and show their output
 
 Screenshot:
   hyprd screenshot              Region screenshot to clipboard
@@ -356,5 +501,8 @@ VPN:
 Notifications:
   hyprd notify hook opencode           Read OpenCode notify JSON from argv/stdin
 	  hyprd notify dunst                   Handle Dunst script callbacks
-  hyprd notify kitty-finish <command>  Emit kitty command-finish notification`)
+  hyprd notify kitty-finish <command>  Emit kitty command-finish notification
+  hyprd agents [list [--json]]         List OpenCode sessions (* = waiting, unseen)
+  hyprd agents jump <n>                Focus session n from the list
+  hyprd agents next-waiting            Focus the session that most needs attention`)
 }
@@ -0,0 +1,292 @@
+package main
+
+// palette.go extracts border accents from a wallpaper frame and caches them per file.
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"image"
+	_ "image/jpeg"
+	_ "image/png"
+	"math"
+	"os"
+	"path/filepath"
+	"slices"
+)
+
+const (
+	paletteSize        = 6
+	paletteClusters    = 12  // k-means clusters before scoring and dedupe
+	paletteGrid        = 96  // samples per axis
+	paletteIterations  = 12  // k-means passes; the grid is small enough to converge well before this
+	paletteMinContrast = 3.0 // WCAG ratio against the frame's mean colour, the usual bar for UI borders
+	paletteMinDistance = 48  // RGB distance between kept colours, so per-workspace accents are distinguishable
+	paletteMinSat      = 0.35
+)
+
+type rgb struct{ R, G, B float64 } // 0..255
+
+// extractPalette returns up to paletteSize "rrggbb" colours, most prominent first.
+//
+// Pixels on a grid are clustered with k-means; clusters score by population weighted
+// toward saturated mid-tones, then each is pushed to paletteMinContrast against the
+// frame's mean colour so the border stays visible over the wallpaper.
+func extractPalette(img image.Image) []string {
+	samples := samplePixels(img)
+	if len(samples) == 0 {
+		return nil
+	}
+	mean := meanColor(samples)
+	centroids, counts := kmeans(samples, paletteClusters)
+
+	type scored struct {
+		c     rgb
+		score float64
+	}
+	var ranked []scored
+	for i, c := range centroids {
+		if counts[i] == 0 {
+			continue
+		}
+		_, s, l := c.hsl()
+		weight := 0.15 + s
+		if l < 0.08 || l > 0.94 { // near-black letterboxing and blown highlights
+			weight *= 0.1
+		}
+		ranked = append(ranked, scored{c, float64(counts[i]) * weight})
+	}
+	slices.SortFunc(ranked, func(a, b scored) int { return cmp.Compare(b.score, a.score) })
+
+	var palette []rgb
+	for _, r := range ranked {
+		c := accentSafe(r.c, mean)
+		if slices.ContainsFunc(palette, func(p rgb) bool { return p.distance(c) < paletteMinDistance }) {
+			continue
+		}
+		palette = append(palette, c)
+		if len(palette) == paletteSize {
+			break
+		}
+	}
+	out := make([]string, len(palette))
+	for i, c := range palette {
+		out[i] = c.hex()
+	}
+	return out
+}
+
+func samplePixels(img image.Image) []rgb {
+	b := img.Bounds()
+	if b.Empty() {
+		return nil
+	}
+	samples := make([]rgb, 0, paletteGrid*paletteGrid)
+	for y := range paletteGrid {
+		for x := range paletteGrid {
+			px := b.Min.X + (2*x+1)*b.Dx()/(2*paletteGrid)
+			py := b.Min.Y + (2*y+1)*b.Dy()/(2*paletteGrid)
+			r, g, bl, _ := img.At(px, py).RGBA()
+			samples = append(samples, rgb{float64(r >> 8), float64(g >> 8), float64(bl >> 8)})
+		}
+	}
+	return samples
+}
+
+func meanColor(samples []rgb) rgb {
+	var sum rgb
+	for _, s := range samples {
+		sum.R += s.R
+		sum.G += s.G
+		sum.B += s.B
+	}
+	n := float64(len(samples))
+	return rgb{sum.R / n, sum.G / n, sum.B / n}
+}
+
+// kmeans clusters samples into k colours, seeded by farthest-point selection so the result
+// is deterministic for a given frame.
+func kmeans(samples []rgb, k int) ([]rgb, []int) {
+	k = min(k, len(samples))
+	centroids := []rgb{samples[0]}
+	nearest := make([]float64, len(samples))
+	for i, s := range samples {
+		nearest[i] = s.distance(centroids[0])
+	}
+	for len(centroids) < k {
+		far := 0
+		for i := range samples {
+			if nearest[i] > nearest[far] {
+				far = i
+			}
+		}
+		centroids = append(centroids, samples[far])
+		for i, s := range samples {
+			nearest[i] = min(nearest[i], s.distance(samples[far]))
+		}
+	}
+
+	counts := make([]int, k)
+	for range paletteIterations {
+		sums := make([]rgb, k)
+		clear(counts)
+		for _, s := range samples {
+			best := 0
+			for j, c := range centroids {
+				if s.distance(c) < s.distance(centroids[best]) {
+					best = j
+				}
+			}
+			counts[best]++
+			sums[best].R += s.R
+			sums[best].G += s.G
+			sums[best].B += s.B
+		}
+		for j := range centroids {
+			if counts[j] > 0 {
+				n := float64(counts[j])
+				centroids[j] = rgb{sums[j].R / n, sums[j].G / n, sums[j].B / n}
+			}
+		}
+	}
+	return centroids, counts
+}
+
+// accentSafe lifts saturation on chromatic colours and moves lightness away from bg until
+// the WCAG contrast ratio reaches paletteMinContrast (or lightness runs out).
+func accentSafe(c, bg rgb) rgb {
+	h, s, l := c.hsl()
+	if s > 0.08 {
+		s = max(s, paletteMinSat)
+	}
+	step := 0.02
+	if bg.luminance() > 0.18 {
+		step = -step
+	}
+	out := hslToRGB(h, s, l)
+	for contrast(out, bg) < paletteMinContrast && l > 0 && l < 1 {
+		l = min(max(l+step, 0), 1)
+		out = hslToRGB(h, s, l)
+	}
+	return out
+}
+
+func (c rgb) distance(o rgb) float64 {
+	return math.Sqrt((c.R-o.R)*(c.R-o.R) + (c.G-o.G)*(c.G-o.G) + (c.B-o.B)*(c.B-o.B))
+}
+
+func (c rgb) hex() string {
+	return fmt.Sprintf("%02x%02x%02x", uint8(math.Round(c.R)), uint8(math.Round(c.G)), uint8(math.Round(c.B)))
+}
+
+// luminance is WCAG relative luminance.
+func (c rgb) luminance() float64 {
+	lin := func(v float64) float64 {
+		v /= 255
+		if v <= 0.04045 {
+			return v / 12.92
+		}
+		return math.Pow((v+0.055)/1.055, 2.4)
+	}
+	return 0.2126*lin(c.R) + 0.7152*lin(c.G) + 0.0722*lin(c.B)
+}
+
+func contrast(a, b rgb) float64 {
+	la, lb := a.luminance(), b.luminance()
+	return (max(la, lb) + 0.05) / (min(la, lb) + 0.05)
+}
+
+func (c rgb) hsl() (h, s, l float64) {
+	r, g, b := c.R/255, c.G/255, c.B/255
+	hi, lo := max(r, g, b), min(r, g, b)
+	l = (hi + lo) / 2
+	if hi == lo {
+		return 0, 0, l
+	}
+	d := hi - lo
+	if l > 0.5 {
+		s = d / (2 - hi - lo)
+	} else {
+		s = d / (hi + lo)
+	}
+	switch hi {
+	case r:
+		h = math.Mod((g-b)/d+6, 6)
+	case g:
+		h = (b-r)/d + 2
+	default:
+		h = (r-g)/d + 4
+	}
+	return h * 60, s, l
+}
+
+func hslToRGB(h, s, l float64) rgb {
+	chroma := (1 - math.Abs(2*l-1)) * s
+	x := chroma * (1 - math.Abs(math.Mod(h/60, 2)-1))
+	m := l - chroma/2
+	var r, g, b float64
+	switch {
+	case h < 60:
+		r, g = chroma, x
+	case h < 120:
+		r, g = x, chroma
+	case h < 180:
+		g, b = chroma, x
+	case h < 240:
+		g, b = x, chroma
+	case h < 300:
+		r, b = x, chroma
+	default:
+		r, b = chroma, x
+	}
+	return rgb{(r + m) * 255, (g + m) * 255, (b + m) * 255}
+}
+
+// paletteCache maps "<path>:<size>:<mtime>" to extracted palettes, so a wallpaper is only
+// decoded once until the file changes.
+type paletteCache map[string][]string
+
+func paletteCachePath() (string, error) {
+	dir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "hyprd", "accent-palettes.json"), nil
+}
+
+func paletteCacheKey(path string) (string, error) {
+	st, err := os.Stat(path)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s:%d:%d", path, st.Size(), st.ModTime().Unix()), nil
+}
+
+func loadPaletteCache() paletteCache {
+	cache := paletteCache{}
+	path, err := paletteCachePath()
+	if err != nil {
+		return cache
+	}
+	if data, err := os.ReadFile(path); err == nil {
+		if err := json.Unmarshal(data, &cache); err != nil {
+			fmt.Fprintf(os.Stderr, "hyprd accent: palette cache: %v\n", err)
+		}
+	}
+	return cache
+}
+
+func (c paletteCache) save() error {
+	path, err := paletteCachePath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o644)
+}
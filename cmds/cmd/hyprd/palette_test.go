@@ -0,0 +1,74 @@
+package main
+
+import (
+	"image"
+	"image/color"
+	"slices"
+	"strconv"
+	"testing"
+)
+
+// stripedFrame is a dark frame with bands of saturated colour, close enough to a
+// wallpaper for the clustering and contrast passes to have something to do.
+func stripedFrame() *image.RGBA {
+	bands := []color.RGBA{
+		{0x12, 0x14, 0x24, 0xff}, // background, repeated so it dominates the mean
+		{0xd0, 0x30, 0x30, 0xff},
+		{0x12, 0x14, 0x24, 0xff},
+		{0x30, 0xa0, 0x40, 0xff},
+		{0x12, 0x14, 0x24, 0xff},
+		{0x30, 0x50, 0xd0, 0xff},
+		{0xe0, 0xc0, 0x30, 0xff},
+		{0x28, 0x1c, 0x3a, 0xff}, // dull, dark band that must be lifted to stay visible
+	}
+	img := image.NewRGBA(image.Rect(0, 0, 320, 180))
+	for y := range 180 {
+		for x := range 320 {
+			img.SetRGBA(x, y, bands[x*len(bands)/320])
+		}
+	}
+	return img
+}
+
+func parseHex(t *testing.T, s string) rgb {
+	t.Helper()
+	v, err := strconv.ParseUint(s, 16, 32)
+	if err != nil || len(s) != 6 {
+		t.Fatalf("palette colour %q is not rrggbb", s)
+	}
+	return rgb{float64(v >> 16 & 0xff), float64(v >> 8 & 0xff), float64(v & 0xff)}
+}
+
+func TestExtractPalette(t *testing.T) {
+	img := stripedFrame()
+	palette := extractPalette(img)
+	if len(palette) < 2 || len(palette) > paletteSize {
+		t.Fatalf("palette has %d colours, want 2..%d: %v", len(palette), paletteSize, palette)
+	}
+	if again := extractPalette(img); !slices.Equal(palette, again) {
+		t.Fatalf("palette not deterministic: %v then %v", palette, again)
+	}
+
+	// hex() rounds each channel, which can shift contrast and distance by a hair.
+	mean := meanColor(samplePixels(img))
+	colours := make([]rgb, len(palette))
+	for i, s := range palette {
+		colours[i] = parseHex(t, s)
+		if c := contrast(colours[i], mean); c < paletteMinContrast-0.05 {
+			t.Errorf("%s: contrast %.2f against the mean, want >= %.1f", s, c, paletteMinContrast)
+		}
+	}
+	for i := range colours {
+		for j := range i {
+			if d := colours[i].distance(colours[j]); d < paletteMinDistance-1 {
+				t.Errorf("%s and %s: distance %.1f, want >= %d", palette[j], palette[i], d, paletteMinDistance)
+			}
+		}
+	}
+}
+
+func TestExtractPaletteEmpty(t *testing.T) {
+	if got := extractPalette(image.NewRGBA(image.Rectangle{})); got != nil {
+		t.Errorf("empty frame: palette = %v, want nil", got)
+	}
+}
@@ -0,0 +1,436 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net"
+	"os"
+	"os/signal"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"dotfiles/cmds/internal/config"
+	"dotfiles/cmds/internal/hyprd/hypr"
+	"dotfiles/cmds/internal/hyprd/state"
+	"dotfiles/cmds/internal/hyprd/wm"
+)
+
+// Trace record kinds, one JSON object per line.
+const (
+	traceHeader  = "header"  // first line: monitors, workspace, clients, daemon state, config
+	traceEvent   = "event"   // raw socket2 line
+	traceClients = "clients" // `j/clients` + active workspace snapshot
+)
+
+const traceDefaultInterval = time.Second
+
+// traceSnapshotEvents trigger a clients snapshot right after they arrive, since their
+// handlers query window geometry that the periodic snapshot may not have caught.
+var traceSnapshotEvents = []string{
+	"openwindow", "closewindow", "movewindow", "movewindowv2", "changefloatingmode",
+	"workspace", "workspacev2", "focusedmon",
+}
+
+// traceRecord is one line of a `hyprd record` trace.
+type traceRecord struct {
+	Kind      string          `json:"kind"`
+	T         int64           `json:"t"` // milliseconds since recording started
+	Event     string          `json:"event,omitempty"`
+	Workspace int             `json:"workspace,omitempty"`
+	Clients   []hypr.Window   `json:"clients,omitempty"`
+	Monitors  []hypr.Monitor  `json:"monitors,omitempty"`
+	State     json.RawMessage `json:"state,omitempty"`     // header only, when the daemon was running
+	Config    string          `json:"config,omitempty"`    // header only: merged hyprd YAML at record time
+	Ancestors map[int][]int   `json:"ancestors,omitempty"` // /proc parent chains of pids first seen in this snapshot
+}
+
+// cmdRecord captures Hyprland's event stream and clients snapshots until interrupted.
+func cmdRecord() {
+	usage := "usage: hyprd record <file> [--interval <duration>]"
+	if len(os.Args) < 3 || strings.HasPrefix(os.Args[2], "-") {
+		fmt.Fprintln(os.Stderr, usage)
+		os.Exit(1)
+	}
+	path := os.Args[2]
+	interval := traceDefaultInterval
+	if len(os.Args) > 3 {
+		d, err := time.ParseDuration(strings.TrimPrefix(strings.Join(os.Args[3:], " "), "--interval "))
+		if os.Args[3] != "--interval" || err != nil || d <= 0 {
+			fmt.Fprintln(os.Stderr, usage)
+			os.Exit(1)
+		}
+		interval = d
+	}
+	if err := record(path, interval); err != nil {
+		fmt.Fprintf(os.Stderr, "hyprd record: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func record(path string, interval time.Duration) error {
+	h, err := hypr.NewClient()
+	if err != nil {
+		return err
+	}
+	conn, err := net.Dial("unix", h.EventSocketPath())
+	if err != nil {
+		return fmt.Errorf("connect to event socket: %w", err)
+	}
+	defer conn.Close()
+
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	w := bufio.NewWriter(f)
+	defer w.Flush()
+
+	var mu sync.Mutex
+	started := time.Now()
+	write := func(r traceRecord) {
+		r.T = time.Since(started).Milliseconds()
+		data, err := json.Marshal(r)
+		if err != nil {
+			return
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		w.Write(append(data, '\n'))
+	}
+	// Swallow matches windows by process ancestry, so the trace carries it for replay.
+	seen := make(map[int]bool)
+	ancestry := func(clients []hypr.Window) map[int][]int {
+		mu.Lock()
+		defer mu.Unlock()
+		var chains map[int][]int
+		for _, c := range clients {
+			if c.Pid <= 0 || seen[c.Pid] {
+				continue
+			}
+			seen[c.Pid] = true
+			if chains == nil {
+				chains = make(map[int][]int)
+			}
+			chains[c.Pid] = slices.Sorted(maps.Keys(wm.ProcessAncestors(c.Pid)))
+		}
+		return chains
+	}
+	snapshot := func(kind string) traceRecord {
+		r := traceRecord{Kind: kind}
+		r.Workspace, _ = h.ActiveWorkspace()
+		r.Clients, _ = h.Clients()
+		r.Ancestors = ancestry(r.Clients)
+		return r
+	}
+
+	header := snapshot(traceHeader)
+	header.Monitors, _ = h.Monitors()
+	if cfg, err := config.HyprYAML(); err == nil {
+		header.Config = string(cfg)
+	} else {
+		fmt.Fprintf(os.Stderr, "hyprd record: config not recorded: %v\n", err)
+	}
+	if client.IsRunning() {
+		if resp, err := client.Send("state"); err == nil && json.Valid([]byte(resp)) {
+			header.State = json.RawMessage(resp)
+		}
+	}
+	write(header)
+
+	stop := make(chan struct{}) // closed once the event stream ends
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
+	defer signal.Stop(sigCh)
+	go func() {
+		select {
+		case <-sigCh:
+			conn.Close() // ends the scan below
+		case <-stop:
+		}
+	}()
+	var ticking sync.WaitGroup
+	ticking.Go(func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				write(snapshot(traceClients))
+			}
+		}
+	})
+
+	fmt.Fprintf(os.Stderr, "hyprd record: writing %s (Ctrl-C to stop)\n", path)
+	events := 0
+	scanner := bufio.NewScanner(conn)
+	for scanner.Scan() {
+		line := scanner.Text()
+		write(traceRecord{Kind: traceEvent, Event: line})
+		events++
+		if name, _, _ := strings.Cut(line, ">>"); slices.Contains(traceSnapshotEvents, name) {
+			write(snapshot(traceClients))
+		}
+	}
+	close(stop)
+	ticking.Wait() // no snapshot may be written during or after the deferred flush
+	fmt.Fprintf(os.Stderr, "hyprd record: %d events in %s\n", events, time.Since(started).Round(time.Second))
+	return nil
+}
+
+// cmdReplay feeds a trace through the EventLoop against a simulated Hyprland and reports
+// the resulting state, optionally diffed against an expected state file.
+func cmdReplay() {
+	usage := "usage: hyprd replay <file> [--expect <state.json>] [--write <state.json>] [--dispatches]"
+	if len(os.Args) < 3 || strings.HasPrefix(os.Args[2], "-") {
+		fmt.Fprintln(os.Stderr, usage)
+		os.Exit(1)
+	}
+	path := os.Args[2]
+	var expect, write string
+	dispatches := false
+	for i := 3; i < len(os.Args); i++ {
+		switch os.Args[i] {
+		case "--expect", "--write":
+			if i+1 >= len(os.Args) {
+				fmt.Fprintln(os.Stderr, usage)
+				os.Exit(1)
+			}
+			if os.Args[i] == "--expect" {
+				expect = os.Args[i+1]
+			} else {
+				write = os.Args[i+1]
+			}
+			i++
+		case "--dispatches":
+			dispatches = true
+		default:
+			fmt.Fprintln(os.Stderr, usage)
+			os.Exit(1)
+		}
+	}
+
+	result, err := replay(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "hyprd replay: %v\n", err)
+		os.Exit(1)
+	}
+	fmt.Fprintf(os.Stderr, "hyprd replay: %d events, %d snapshots, %d dispatches\n", result.events, result.snapshots, len(result.dispatched))
+	if dispatches {
+		for _, lua := range result.dispatched {
+			fmt.Fprintln(os.Stderr, "  "+lua)
+		}
+	}
+
+	if write != "" {
+		if err := os.WriteFile(write, append(result.state, '\n'), 0o644); err != nil {
+			fmt.Fprintf(os.Stderr, "hyprd replay: %v\n", err)
+			os.Exit(1)
+		}
+	}
+	if expect == "" {
+		if write == "" {
+			fmt.Println(string(result.state))
+		}
+		return
+	}
+	want, err := os.ReadFile(expect)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "hyprd replay: %v\n", err)
+		os.Exit(1)
+	}
+	changes, err := diffState(want, result.state)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "hyprd replay: %v\n", err)
+		os.Exit(1)
+	}
+	if len(changes) == 0 {
+		fmt.Println("replay: state matches " + expect)
+		return
+	}
+	for _, c := range changes {
+		fmt.Println(c.format("want", "got "))
+	}
+	os.Exit(1)
+}
+
+type replayResult struct {
+	state      []byte // indented final State JSON
+	events     int
+	snapshots  int
+	dispatched []string
+}
+
+func replay(path string) (replayResult, error) {
+	records, err := readTrace(path)
+	if err != nil {
+		return replayResult{}, err
+	}
+	if len(records) == 0 || records[0].Kind != traceHeader {
+		return replayResult{}, fmt.Errorf("%s: missing trace header", path)
+	}
+
+	sim, err := hypr.NewSim()
+	if err != nil {
+		return replayResult{}, err
+	}
+	defer sim.Close()
+
+	header := records[0]
+	cfg, err := replayConfig(header)
+	if err != nil {
+		return replayResult{}, err
+	}
+	st := state.NewState(&cfg)
+	if len(header.State) > 0 {
+		if err := st.Restore(header.State); err != nil {
+			return replayResult{}, fmt.Errorf("restore recorded state: %w", err)
+		}
+	}
+	sim.SetMonitors(header.Monitors)
+	apply := func(r traceRecord) {
+		sim.SetClients(r.Clients)
+		if r.Workspace > 0 {
+			sim.SetWorkspace(r.Workspace)
+		}
+	}
+	apply(header)
+
+	ancestors := make(map[int]map[int]bool)
+	for _, r := range records {
+		for pid, chain := range r.Ancestors {
+			ancestors[pid] = make(map[int]bool, len(chain))
+			for _, ppid := range chain {
+				ancestors[pid][ppid] = true
+			}
+		}
+	}
+
+	done := make(chan struct{})
+	defer close(done)
+	loop := NewEventLoop(sim.Client(), st, nil, nil, nil, nil, done)
+	loop.ancestors = func(pid int) map[int]bool { return ancestors[pid] }
+	if len(header.State) == 0 {
+		if err := loop.syncState(); err != nil {
+			return replayResult{}, err
+		}
+	}
+
+	result := replayResult{}
+	for i := 1; i < len(records); i++ {
+		r := records[i]
+		if r.Kind == traceClients {
+			apply(r)
+			result.snapshots++
+			continue
+		}
+		if r.Kind != traceEvent {
+			continue
+		}
+		// The recorder snapshots right after window events, so the snapshots up to the
+		// next event are what Hyprland reported while the daemon handled this one.
+		for i+1 < len(records) && records[i+1].Kind == traceClients {
+			i++
+			apply(records[i])
+			result.snapshots++
+		}
+		loop.handleEvent(r.Event)
+		loop.handlers.Wait() // background handlers finish against this event's snapshots
+		result.events++
+	}
+
+	data, err := st.JSON()
+	if err != nil {
+		return replayResult{}, err
+	}
+	var indented map[string]any
+	if err := json.Unmarshal(data, &indented); err != nil {
+		return replayResult{}, err
+	}
+	result.state, err = json.MarshalIndent(indented, "", "  ")
+	if err != nil {
+		return replayResult{}, err
+	}
+	result.dispatched = sim.Dispatched()
+	return result, nil
+}
+
+// replayConfig is the config the trace was recorded under. Traces from before configs
+// were recorded fall back to the current hyprd.yaml.
+func replayConfig(header traceRecord) (config.HyprConfig, error) {
+	if header.Config == "" {
+		fmt.Fprintln(os.Stderr, "hyprd replay: trace has no recorded config, using the current hyprd.yaml")
+		return config.LoadHypr(), nil
+	}
+	cfg, err := config.ParseHypr([]byte(header.Config))
+	if err != nil {
+		return cfg, fmt.Errorf("recorded config: %w", err)
+	}
+	return cfg, nil
+}
+
+func readTrace(path string) ([]traceRecord, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var records []traceRecord
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024) // clients snapshots are long lines
+	for line := 1; scanner.Scan(); line++ {
+		var r traceRecord
+		if err := json.Unmarshal(scanner.Bytes(), &r); err != nil {
+			return nil, fmt.Errorf("%s:%d: %w", path, line, err)
+		}
+		records = append(records, r)
+	}
+	return records, scanner.Err()
+}
+
+// stateChange is one top-level State field that differs between two JSON dumps.
+type stateChange struct {
+	Key      string
+	From, To json.RawMessage // null when the field is absent
+}
+
+func (c stateChange) format(from, to string) string {
+	return fmt.Sprintf("%s:\n  %s %s\n  %s %s", c.Key, from, c.From, to, c.To)
+}
+
+// diffState compares two State JSON documents key by key, in key order.
+func diffState(from, to []byte) ([]stateChange, error) {
+	var a, b map[string]any
+	if err := json.Unmarshal(from, &a); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(to, &b); err != nil {
+		return nil, err
+	}
+	keys := slices.Collect(maps.Keys(a))
+	for k := range b {
+		if _, ok := a[k]; !ok {
+			keys = append(keys, k)
+		}
+	}
+	slices.Sort(keys)
+	var changes []stateChange
+	for _, k := range keys {
+		if reflect.DeepEqual(a[k], b[k]) {
+			continue
+		}
+		fromJSON, _ := json.Marshal(a[k])
+		toJSON, _ := json.Marshal(b[k])
+		changes = append(changes, stateChange{Key: k, From: fromJSON, To: toJSON})
+	}
+	return changes, nil
+}
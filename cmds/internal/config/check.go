@@ -0,0 +1,738 @@
+package config
+
+// check.go statically validates hyprd.yaml references without a running Hyprland session.
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// CWDResolveModes lists the supported TabDef/TabPane cwd_resolve values.
+var CWDResolveModes = []string{"recent-git"}
+
+// kittyLocations and kittyLayouts mirror the values accepted by `kitty @ launch --location` and goto-layout.
+var (
+	kittyLocations = []string{"after", "before", "default", "first", "hsplit", "last", "neighbor", "split", "vsplit"}
+	kittyLayouts   = []string{"fat", "grid", "horizontal", "splits", "stack", "tall", "vertical"}
+)
+
+// CheckIssue is one finding from CheckHypr, anchored to the YAML node that caused it.
+type CheckIssue struct {
+	Line     int
+	Column   int
+	Path     string // dotted key path, e.g. sessions.4.leadpier.tabs.editor
+	Severity string // "error" or "warning"
+	Message  string
+}
+
+func (i CheckIssue) String() string {
+	return fmt.Sprintf("%d:%d: %s: %s: %s", i.Line, i.Column, i.Severity, i.Path, i.Message)
+}
+
+// HyprCheckOptions injects lookups that live outside config, so checks stay offline and testable.
+type HyprCheckOptions struct {
+	SnapshotExists func(name string) bool  // browser snapshot resolver; nil skips snapshot checks
+	LookPath       func(name string) error // executable resolver; nil uses exec.LookPath
+	Home           string                  // base for session project paths; empty uses $HOME
+	Palette        *Palette                // theme palette for notify style references; nil only checks literal hex
+}
+
+// CheckHypr decodes hyprd YAML and cross-validates session bodies, tab profiles, snapshots, and executables.
+//
+// Decode failures are returned as issues too, so one run reports everything it can locate.
+// Unresolved references are errors; missing executables and directories are warnings because they vary by host.
+func CheckHypr(data []byte, opts HyprCheckOptions) []CheckIssue {
+	if opts.LookPath == nil {
+		opts.LookPath = func(name string) error {
+			_, err := exec.LookPath(name)
+			return err
+		}
+	}
+	if opts.Home == "" {
+		opts.Home, _ = os.UserHomeDir()
+	}
+	c := &hyprChecker{opts: opts}
+
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		c.decodeError(err)
+		return c.issues
+	}
+	if len(doc.Content) == 0 {
+		c.add(&doc, "", "error", "empty config")
+		return c.issues
+	}
+	root := doc.Content[0]
+
+	if err := root.Decode(&c.cfg); err != nil {
+		c.decodeError(err)
+		return c.issues
+	}
+
+	c.checkWindows(root)
+	c.checkBackground(root)
+	c.checkInit(root)
+	c.checkIdle(root)
+	c.checkLock(root)
+	c.checkNotify(root)
+	c.checkShare(root)
+	c.checkTabs(root)
+	c.checkSessions(root)
+	c.checkTemplates(root)
+	c.checkProjects(root)
+	c.checkHooks(root)
+
+	sort.SliceStable(c.issues, func(i, j int) bool {
+		if c.issues[i].Line != c.issues[j].Line {
+			return c.issues[i].Line < c.issues[j].Line
+		}
+		return c.issues[i].Column < c.issues[j].Column
+	})
+	return c.issues
+}
+
+type hyprChecker struct {
+	cfg    HyprConfig
+	opts   HyprCheckOptions
+	issues []CheckIssue
+}
+
+func (c *hyprChecker) add(n *yaml.Node, path, severity, format string, args ...any) {
+	issue := CheckIssue{Path: path, Severity: severity, Message: fmt.Sprintf(format, args...)}
+	if n != nil {
+		issue.Line, issue.Column = n.Line, n.Column
+	}
+	c.issues = append(c.issues, issue)
+}
+
+func (c *hyprChecker) errorf(n *yaml.Node, path, format string, args ...any) {
+	c.add(n, path, "error", format, args...)
+}
+
+func (c *hyprChecker) warnf(n *yaml.Node, path, format string, args ...any) {
+	c.add(n, path, "warning", format, args...)
+}
+
+var envName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+var decodeLinePattern = regexp.MustCompile(`line (\d+): (.*)`)
+
+// decodeError splits yaml.v3 "line N: ..." messages into positioned issues.
+func (c *hyprChecker) decodeError(err error) {
+	messages := []string{err.Error()}
+	var typeErr *yaml.TypeError
+	if errors.As(err, &typeErr) {
+		messages = typeErr.Errors
+	}
+	for _, msg := range messages {
+		issue := CheckIssue{Path: "(decode)", Severity: "error", Message: msg}
+		if m := decodeLinePattern.FindStringSubmatch(msg); m != nil {
+			issue.Line, _ = strconv.Atoi(m[1])
+			issue.Message = m[2]
+		}
+		c.issues = append(c.issues, issue)
+	}
+}
+
+func (c *hyprChecker) lookPath(n *yaml.Node, path, name string) {
+	if name == "" {
+		return
+	}
+	if err := c.opts.LookPath(name); err != nil {
+		c.warnf(n, path, "executable %q not found in PATH", name)
+	}
+}
+
+// ╭──────────────────────────────────────────────────────────────────────────────╮
+// │ sections                                                                     │
+// ╰──────────────────────────────────────────────────────────────────────────────╯
+
+func (c *hyprChecker) checkWindows(root *yaml.Node) {
+	split := mappingValue(mappingValue(root, "windows"), "split")
+	for _, key := range []string{"xs", "default", "lg"} {
+		node := mappingValue(split, key)
+		if node == nil {
+			continue
+		}
+		ratio, err := strconv.ParseFloat(node.Value, 64)
+		if err != nil || ratio <= 0 || ratio >= 1 {
+			c.errorf(node, "windows.split."+key, "split ratio %q must be a number between 0 and 1", node.Value)
+		}
+	}
+
+	corner := mappingValue(mappingValue(mappingValue(root, "windows"), "pip"), "corner")
+	if corner != nil && !slices.Contains(PiPCorners, corner.Value) {
+		c.errorf(corner, "windows.pip.corner", "unknown corner %q (want %s)", corner.Value, strings.Join(PiPCorners, "|"))
+	}
+}
+
+func (c *hyprChecker) checkBackground(root *yaml.Node) {
+	for i, node := range sequenceItems(mappingValue(mappingValue(root, "background"), "playlist")) {
+		path := fmt.Sprintf("background.playlist.%d", i)
+		if file := mappingValue(node, "file"); file == nil || file.Value == "" {
+			c.errorf(node, path, "missing file")
+		}
+		if hours := mappingValue(node, "hours"); hours != nil {
+			if _, _, err := ParseHours(hours.Value); err != nil {
+				c.errorf(hours, path+".hours", "%v", err)
+			}
+		}
+		for _, day := range sequenceItems(mappingValue(node, "days")) {
+			if !slices.Contains(Weekdays, day.Value) {
+				c.errorf(day, path+".days", "unknown day %q (want %s)", day.Value, strings.Join(Weekdays, "|"))
+			}
+		}
+		for _, session := range sequenceItems(mappingValue(node, "sessions")) {
+			if _, ok := c.cfg.Sessions[session.Value]; !ok {
+				c.errorf(session, path+".sessions", "undefined session %q", session.Value)
+			}
+		}
+	}
+}
+
+// checkInit validates boot profiles: unique names, well-formed rules, and references to
+// sessions, VPN connections, and wallpapers that exist.
+func (c *hyprChecker) checkInit(root *yaml.Node) {
+	names := make(map[string]bool)
+	for i, node := range sequenceItems(mappingValue(mappingValue(root, "init"), "profiles")) {
+		path := fmt.Sprintf("init.profiles.%d", i)
+		if name := mappingValue(node, "name"); name == nil || name.Value == "" {
+			c.errorf(node, path, "missing name")
+		} else if names[name.Value] {
+			c.errorf(name, path+".name", "duplicate profile %q", name.Value)
+		} else {
+			names[name.Value] = true
+		}
+
+		when := mappingValue(node, "when")
+		if hours := mappingValue(when, "hours"); hours != nil {
+			if _, _, err := ParseHours(hours.Value); err != nil {
+				c.errorf(hours, path+".when.hours", "%v", err)
+			}
+		}
+		for _, day := range sequenceItems(mappingValue(when, "days")) {
+			if !slices.Contains(Weekdays, day.Value) {
+				c.errorf(day, path+".when.days", "unknown day %q (want %s)", day.Value, strings.Join(Weekdays, "|"))
+			}
+		}
+
+		perWS := make(map[int]string)
+		for _, n := range sequenceItems(mappingValue(node, "sessions")) {
+			s, ok := c.cfg.Sessions[n.Value]
+			if !ok {
+				c.errorf(n, path+".sessions", "undefined session %q", n.Value)
+				continue
+			}
+			if prev, dup := perWS[s.Workspace]; dup {
+				c.errorf(n, path+".sessions", "sessions %q and %q both open on ws%d", prev, n.Value, s.Workspace)
+			}
+			perWS[s.Workspace] = n.Value
+		}
+		for _, n := range sequenceItems(mappingValue(node, "vpn")) {
+			if _, ok := c.cfg.VPN.Connections[n.Value]; !ok {
+				c.errorf(n, path+".vpn", "unknown vpn connection %q (add it under vpn.connections)", n.Value)
+			}
+		}
+		if n := mappingValue(node, "wallpaper"); n != nil && n.Value != "" {
+			dir := ExpandPath(c.cfg.Background.VideoPath)
+			if _, err := os.Stat(filepath.Join(dir, n.Value)); err != nil {
+				c.warnf(n, path+".wallpaper", "wallpaper %q not found in %s", n.Value, c.cfg.Background.VideoPath)
+			}
+		}
+	}
+}
+
+func (c *hyprChecker) checkIdle(root *yaml.Node) {
+	prev := -1
+	for i, node := range sequenceItems(mappingValue(mappingValue(root, "idle"), "stages")) {
+		path := fmt.Sprintf("idle.stages.%d", i)
+		if action := mappingValue(node, "action"); action == nil {
+			c.errorf(node, path, "missing action")
+		} else if !slices.Contains(IdleActions, action.Value) {
+			c.errorf(action, path+".action", "unknown action %q (want %s)", action.Value, strings.Join(IdleActions, "|"))
+		}
+		if after := mappingValue(node, "after"); after != nil {
+			n, err := strconv.Atoi(after.Value)
+			if err != nil || n < 0 {
+				c.errorf(after, path+".after", "after %q must be a non-negative number of seconds", after.Value)
+			} else if n < prev {
+				c.errorf(after, path+".after", "stage runs before the previous one (%d < %d)", n, prev)
+			} else {
+				prev = n
+			}
+		}
+		for _, inhibit := range sequenceItems(mappingValue(node, "inhibit")) {
+			if !slices.Contains(IdleInhibitors, inhibit.Value) {
+				c.errorf(inhibit, path+".inhibit", "unknown inhibitor %q (want %s)", inhibit.Value, strings.Join(IdleInhibitors, "|"))
+			}
+		}
+	}
+}
+
+// checkLock validates disabled built-ins and that each hook sets exactly one of run or hyprd.
+// Hooks may not call lock or idle verbs, which would re-enter the lock they run inside.
+func (c *hyprChecker) checkLock(root *yaml.Node) {
+	lock := mappingValue(root, "lock")
+	for _, n := range sequenceItems(mappingValue(lock, "disable")) {
+		if !slices.Contains(LockBuiltins, n.Value) {
+			c.errorf(n, "lock.disable", "unknown built-in %q (want %s)", n.Value, strings.Join(LockBuiltins, "|"))
+		}
+	}
+	phases := []string{"pre_pseudo", "pre_lock", "post_unlock", "post_pseudo"}
+	for _, phase := range mappingPairs(mappingValue(lock, "hooks")) {
+		if !slices.Contains(phases, phase.key.Value) {
+			c.errorf(phase.key, "lock.hooks", "unknown hook phase %q (want %s)", phase.key.Value, strings.Join(phases, "|"))
+		}
+		for i, node := range sequenceItems(phase.value) {
+			path := fmt.Sprintf("lock.hooks.%s.%d", phase.key.Value, i)
+			run, hyprd := mappingValue(node, "run"), mappingValue(node, "hyprd")
+			switch {
+			case run == nil && hyprd == nil:
+				c.errorf(node, path, "hook needs run or hyprd")
+			case run != nil && hyprd != nil:
+				c.errorf(node, path, "hook sets both run and hyprd")
+			case run != nil:
+				c.lookPath(run, path+".run", commandExecutable(run.Value))
+			default:
+				if verb, _, _ := strings.Cut(strings.TrimSpace(hyprd.Value), " "); verb == "lock" || verb == "idle" {
+					c.errorf(hyprd, path+".hyprd", "hook cannot run %q from inside a lock", verb)
+				}
+			}
+		}
+	}
+}
+
+func (c *hyprChecker) checkNotify(root *yaml.Node) {
+	for _, style := range mappingPairs(mappingValue(mappingValue(root, "notify"), "styles")) {
+		for _, field := range mappingPairs(style.value) {
+			path := "notify.styles." + style.key.Value + "." + field.key.Value
+			ref := field.value.Value
+			if ref == "" || (!strings.HasPrefix(ref, "#") && c.opts.Palette == nil) {
+				continue
+			}
+			palette := Palette{}
+			if c.opts.Palette != nil {
+				palette = *c.opts.Palette
+			}
+			if _, err := palette.Resolve(ref); err != nil {
+				c.errorf(field.value, path, "%v", err)
+			}
+		}
+	}
+	events := mappingValue(mappingValue(root, "notify"), "agent_events")
+	for _, pair := range mappingPairs(events) {
+		style := mappingValue(pair.value, "style")
+		if style == nil || style.Value == "" {
+			continue
+		}
+		if _, ok := c.cfg.Notify.Styles[style.Value]; !ok {
+			c.errorf(style, "notify.agent_events."+pair.key.Value+".style", "undefined style %q", style.Value)
+		}
+	}
+}
+
+func (c *hyprChecker) checkShare(root *yaml.Node) {
+	share := mappingValue(root, "share")
+	if notify := mappingValue(share, "notify"); notify != nil && notify.Value != "" && !slices.Contains(ShareNotifyModes, notify.Value) {
+		c.errorf(notify, "share.notify", "unknown notify mode %q (want %s)", notify.Value, strings.Join(ShareNotifyModes, "|"))
+	}
+	if accent := mappingValue(share, "accent"); accent != nil && accent.Value != "" && !isHexColor(accent.Value) {
+		c.errorf(accent, "share.accent", "accent %q must be #rrggbb", accent.Value)
+	}
+}
+
+func (c *hyprChecker) checkTabs(root *yaml.Node) {
+	prefixes := make(map[string]string)
+	for _, pair := range mappingPairs(mappingValue(root, "tabs")) {
+		name := pair.key.Value
+		path := "tabs." + name
+		profile := c.cfg.Tabs[name]
+
+		if prefixNode := mappingValue(pair.value, "prefix"); prefixNode != nil && profile.Prefix != "" {
+			if owner, dup := prefixes[profile.Prefix]; dup {
+				c.errorf(prefixNode, path+".prefix", "prefix %q already used by profile %q", profile.Prefix, owner)
+			}
+			prefixes[profile.Prefix] = name
+		} else {
+			c.warnf(pair.key, path, "profile has no prefix; profile detection will not find it")
+		}
+
+		tabNodes := sequenceItems(mappingValue(pair.value, "tabs"))
+		seen := make(map[string]bool)
+		for i, tabNode := range tabNodes {
+			if i >= len(profile.Tabs) {
+				break
+			}
+			tab := profile.Tabs[i]
+			tabPath := fmt.Sprintf("%s.tabs[%d]", path, i)
+			if tab.Name == "" {
+				c.errorf(tabNode, tabPath, "tab has no name")
+			} else if seen[tab.Name] {
+				c.errorf(tabNode, tabPath, "duplicate tab name %q", tab.Name)
+			}
+			seen[tab.Name] = true
+			c.checkTab(tabNode, tabPath, tab)
+		}
+
+		if focus := mappingValue(pair.value, "focus"); focus != nil && focus.Value != "" && !seen[focus.Value] {
+			c.errorf(focus, path+".focus", "focus tab %q is not defined in this profile", focus.Value)
+		}
+	}
+}
+
+func (c *hyprChecker) checkTab(node *yaml.Node, path string, tab TabDef) {
+	if n := mappingValue(node, "cwd_resolve"); n != nil && n.Value != "" && !slices.Contains(CWDResolveModes, n.Value) {
+		c.errorf(n, path+".cwd_resolve", "unsupported cwd_resolve %q (want %s)", n.Value, strings.Join(CWDResolveModes, "|"))
+	}
+	if n := mappingValue(node, "layout"); n != nil && n.Value != "" {
+		layout, _, _ := strings.Cut(n.Value, ":")
+		if !slices.Contains(kittyLayouts, layout) {
+			c.errorf(n, path+".layout", "unknown kitty layout %q", layout)
+		}
+	}
+	if n := mappingValue(node, "focus_pane"); n != nil && (tab.FocusPane < 0 || tab.FocusPane > len(tab.Panes)) {
+		c.errorf(n, path+".focus_pane", "pane %d out of range (tab has %d panes)", tab.FocusPane, len(tab.Panes)+1)
+	}
+	for _, pair := range mappingPairs(mappingValue(node, "actions")) {
+		pane := mappingValue(pair.value, "pane")
+		if action := tab.Actions[pair.key.Value]; pane != nil && (action.Pane < 0 || action.Pane > len(tab.Panes)) {
+			c.errorf(pane, path+".actions."+pair.key.Value+".pane", "pane %d out of range (tab has %d panes)", action.Pane, len(tab.Panes)+1)
+		}
+	}
+	for i, n := range sequenceItems(mappingValue(node, "executables")) {
+		c.lookPath(n, fmt.Sprintf("%s.executables[%d]", path, i), n.Value)
+	}
+
+	for i, paneNode := range sequenceItems(mappingValue(node, "panes")) {
+		panePath := fmt.Sprintf("%s.panes[%d]", path, i)
+		if n := mappingValue(paneNode, "location"); n != nil && n.Value != "" && !slices.Contains(kittyLocations, n.Value) {
+			c.errorf(n, panePath+".location", "unknown kitty launch location %q", n.Value)
+		}
+		if n := mappingValue(paneNode, "cwd_resolve"); n != nil && n.Value != "" && !slices.Contains(CWDResolveModes, n.Value) {
+			c.errorf(n, panePath+".cwd_resolve", "unsupported cwd_resolve %q (want %s)", n.Value, strings.Join(CWDResolveModes, "|"))
+		}
+		for j, n := range sequenceItems(mappingValue(paneNode, "executables")) {
+			c.lookPath(n, fmt.Sprintf("%s.executables[%d]", panePath, j), n.Value)
+		}
+	}
+}
+
+// checkSessions validates every session and rejects names that collide with layout verbs.
+func (c *hyprChecker) checkSessions(root *yaml.Node) {
+	for _, group := range mappingPairs(mappingValue(root, "sessions")) {
+		for _, pair := range mappingPairs(group.value) {
+			name := strings.TrimSpace(pair.key.Value)
+			session, ok := c.cfg.Sessions[name]
+			if !ok {
+				continue
+			}
+			path := fmt.Sprintf("sessions.%s.%s", group.key.Value, name)
+			if slices.Contains(LayoutVerbs, name) {
+				c.errorf(pair.key, path, "session name %q is a layout subcommand and could not be opened", name)
+			}
+			c.checkSession(pair.value, path, session)
+		}
+	}
+}
+
+// checkTemplates validates each template as a session and flags ${var} placeholders that
+// neither vars nor the built-in name/workspace define.
+func (c *hyprChecker) checkTemplates(root *yaml.Node) {
+	for _, pair := range mappingPairs(mappingValue(root, "templates")) {
+		name := strings.TrimSpace(pair.key.Value)
+		t, ok := c.cfg.Templates[name]
+		if !ok {
+			continue
+		}
+		path := "templates." + name
+		c.checkSession(pair.value, path, t.Session)
+
+		defined := func(key string) bool {
+			_, ok := t.Vars[key]
+			return ok || key == "name" || key == "workspace"
+		}
+		walkScalars(pair.value, func(n *yaml.Node) {
+			for _, match := range templateVar.FindAllStringSubmatch(n.Value, -1) {
+				if !defined(match[1]) {
+					c.errorf(n, path, "undefined variable ${%s} (add it under vars)", match[1])
+				}
+			}
+		})
+		for _, profile := range t.Tabs {
+			for _, tab := range c.cfg.Tabs[profile].Tabs {
+				for _, match := range templateVar.FindAllStringSubmatch(tab.CWD, -1) {
+					if !defined(match[1]) {
+						c.errorf(pair.value, path+".tabs", "tab profile %q cwd uses ${%s}, which the template does not define", profile, match[1])
+					}
+				}
+			}
+		}
+	}
+}
+
+// checkProjects validates project roots and the template, tab profile, snapshot, and VPNs
+// each project references; project sessions take the project name, so it must be free.
+func (c *hyprChecker) checkProjects(root *yaml.Node) {
+	for _, pair := range mappingPairs(mappingValue(root, "projects")) {
+		name := strings.TrimSpace(pair.key.Value)
+		p, ok := c.cfg.Projects[name]
+		if !ok {
+			continue
+		}
+		path := "projects." + name
+		if _, clash := c.cfg.Sessions[name]; clash {
+			c.errorf(pair.key, path, "project name %q is already a session name", name)
+		}
+		if slices.Contains(LayoutVerbs, name) {
+			c.errorf(pair.key, path, "project name %q is a layout subcommand and could not be reopened", name)
+		}
+
+		rootNode := mappingValue(pair.value, "root")
+		switch dir := ExpandPath(p.Root); {
+		case p.Root == "":
+			c.errorf(pair.value, path+".root", "project needs a root")
+		case !filepath.IsAbs(dir):
+			c.errorf(rootNode, path+".root", "root %q must be absolute or start with ~/", p.Root)
+		case dir != c.opts.Home && !strings.HasPrefix(dir, c.opts.Home+"/"):
+			c.errorf(rootNode, path+".root", "root %s is outside $HOME", p.Root)
+		default:
+			if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+				c.warnf(rootNode, path+".root", "project directory %s does not exist", p.Root)
+			}
+		}
+
+		body := defaultProjectTemplate.Body
+		if p.Template != "" {
+			t, ok := c.cfg.Templates[p.Template]
+			if !ok {
+				c.errorf(mappingValue(pair.value, "template"), path+".template", "undefined template %q", p.Template)
+			}
+			body = t.Body
+		}
+		if n := mappingValue(pair.value, "tabs"); n != nil {
+			if _, ok := c.cfg.Tabs[p.Tabs]; !ok {
+				c.errorf(n, path+".tabs", "undefined tab profile %q", p.Tabs)
+			} else if p.Template != "" && !slices.Contains(body, "editor") {
+				c.warnf(n, path+".tabs", "template %q has no editor window for tab profile %q", p.Template, p.Tabs)
+			}
+		}
+		if p.Browser != "" && c.opts.SnapshotExists != nil && !c.opts.SnapshotExists(p.Browser) {
+			c.errorf(mappingValue(pair.value, "browser"), path+".browser", "browser snapshot %q not found", p.Browser)
+		}
+		c.checkRequires(pair.value, path)
+	}
+}
+
+// checkHooks validates event names and that each hook has a command; class filters are
+// flagged on events that carry no window, where they would never match.
+func (c *hyprChecker) checkHooks(root *yaml.Node) {
+	hooks := mappingValue(root, "hooks")
+	for _, key := range []string{"concurrency", "timeout"} {
+		if n := mappingValue(hooks, key); n != nil {
+			if v, err := strconv.Atoi(n.Value); err != nil || v < 0 {
+				c.errorf(n, "hooks."+key, "%s %q must be a non-negative integer", key, n.Value)
+			}
+		}
+	}
+	for _, event := range mappingPairs(mappingValue(hooks, "events")) {
+		if !slices.Contains(HookEvents, event.key.Value) {
+			c.errorf(event.key, "hooks.events", "unknown event %q (want %s)", event.key.Value, strings.Join(HookEvents, "|"))
+			continue
+		}
+		windowEvent := strings.HasPrefix(event.key.Value, "window_")
+		for i, node := range sequenceItems(event.value) {
+			path := fmt.Sprintf("hooks.events.%s.%d", event.key.Value, i)
+			run := mappingValue(node, "run")
+			if run == nil || strings.TrimSpace(run.Value) == "" {
+				c.errorf(node, path, "hook needs run")
+			} else {
+				c.lookPath(run, path+".run", commandExecutable(run.Value))
+			}
+			if class := mappingValue(node, "class"); class != nil && !windowEvent {
+				c.warnf(class, path+".class", "class filter never matches %s, which has no window", event.key.Value)
+			}
+		}
+	}
+}
+
+func (c *hyprChecker) checkSession(node *yaml.Node, path string, s Session) {
+	bodyNode := mappingValue(node, "body")
+	switch {
+	case len(s.Body) == 0 && s.Command == "":
+		c.errorf(node, path, "session needs a body or a command")
+	case len(s.Body) > 0 && s.Command != "":
+		c.errorf(bodyNode, path, "session sets both body and command; command is ignored")
+	case len(s.Body) == 1:
+		c.errorf(bodyNode, path+".body", "body needs at least two members (master and slave)")
+	}
+
+	seen := make(map[string]bool)
+	for i, n := range sequenceItems(bodyNode) {
+		memberPath := fmt.Sprintf("%s.body[%d]", path, i)
+		if _, ok := ThreeBody[n.Value]; !ok {
+			c.errorf(n, memberPath, "unknown body role %q (want %s)", n.Value, strings.Join(threeBodyRoles(), "|"))
+		}
+		if seen[n.Value] {
+			c.errorf(n, memberPath, "duplicate body role %q", n.Value)
+		}
+		seen[n.Value] = true
+	}
+
+	layout := mappingValue(node, "layout")
+	for _, key := range []string{"master", "slave", "shadow"} {
+		n := mappingValue(layout, key)
+		if n != nil && n.Value != "" && !seen[n.Value] {
+			c.errorf(n, path+".layout."+key, "layout role %q is not in body", n.Value)
+		}
+	}
+
+	for _, pair := range mappingPairs(mappingValue(node, "tabs")) {
+		tabsPath := path + ".tabs." + pair.key.Value
+		if !seen[pair.key.Value] {
+			c.errorf(pair.key, tabsPath, "tabs key %q is not in body", pair.key.Value)
+		}
+		if _, ok := c.cfg.Tabs[pair.value.Value]; !ok {
+			c.errorf(pair.value, tabsPath, "undefined tab profile %q", pair.value.Value)
+		}
+	}
+
+	browserNode := mappingValue(node, "browser")
+	if snapshotNode := mappingValue(browserNode, "snapshot"); snapshotNode != nil {
+		browserNode = snapshotNode
+	}
+	if seen["browser"] && s.Browser.Snapshot == "" && len(s.Browser.AllURLs()) == 0 {
+		c.errorf(bodyNode, path+".browser", "browser body member requires a browser snapshot or urls")
+	}
+	if s.Browser.Snapshot != "" && c.opts.SnapshotExists != nil && !c.opts.SnapshotExists(s.Browser.Snapshot) {
+		c.errorf(browserNode, path+".browser", "browser snapshot %q not found", s.Browser.Snapshot)
+	}
+
+	if s.Command != "" && !templateVar.MatchString(commandExecutable(s.Command)) {
+		c.lookPath(mappingValue(node, "command"), path+".command", commandExecutable(s.Command))
+	}
+	if s.Project != "" && !templateVar.MatchString(s.Project) {
+		if info, err := os.Stat(filepath.Join(c.opts.Home, s.Project)); err != nil || !info.IsDir() {
+			c.warnf(mappingValue(node, "project"), path+".project", "project directory ~/%s does not exist", s.Project)
+		}
+	}
+	c.checkRequires(mappingValue(node, "requires"), path+".requires")
+}
+
+// checkRequires validates session prerequisites: VPNs must be configured connections, reach
+// entries host:port pairs, and env entries variable names.
+func (c *hyprChecker) checkRequires(node *yaml.Node, path string) {
+	for i, n := range sequenceItems(mappingValue(node, "vpn")) {
+		if _, ok := c.cfg.VPN.Connections[n.Value]; !ok {
+			c.errorf(n, fmt.Sprintf("%s.vpn[%d]", path, i), "unknown vpn connection %q (add it under vpn.connections)", n.Value)
+		}
+	}
+	for i, n := range sequenceItems(mappingValue(node, "reach")) {
+		if templateVar.MatchString(n.Value) {
+			continue
+		}
+		host, port, err := net.SplitHostPort(n.Value)
+		if _, perr := strconv.ParseUint(port, 10, 16); err != nil || host == "" || perr != nil {
+			c.errorf(n, fmt.Sprintf("%s.reach[%d]", path, i), "reach %q is not host:port", n.Value)
+		}
+	}
+	for i, n := range sequenceItems(mappingValue(node, "env")) {
+		if !envName.MatchString(n.Value) {
+			c.errorf(n, fmt.Sprintf("%s.env[%d]", path, i), "invalid environment variable name %q", n.Value)
+		}
+	}
+}
+
+// ╭──────────────────────────────────────────────────────────────────────────────╮
+// │ yaml node helpers                                                            │
+// ╰──────────────────────────────────────────────────────────────────────────────╯
+
+type nodePair struct {
+	key   *yaml.Node
+	value *yaml.Node
+}
+
+func mappingValue(n *yaml.Node, key string) *yaml.Node {
+	for _, pair := range mappingPairs(n) {
+		if pair.key.Value == key {
+			return pair.value
+		}
+	}
+	return nil
+}
+
+func mappingPairs(n *yaml.Node) []nodePair {
+	if n == nil || n.Kind != yaml.MappingNode {
+		return nil
+	}
+	pairs := make([]nodePair, 0, len(n.Content)/2)
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		pairs = append(pairs, nodePair{key: n.Content[i], value: n.Content[i+1]})
+	}
+	return pairs
+}
+
+// walkScalars calls fn for every scalar below n.
+func walkScalars(n *yaml.Node, fn func(*yaml.Node)) {
+	if n == nil {
+		return
+	}
+	if n.Kind == yaml.ScalarNode {
+		fn(n)
+		return
+	}
+	for _, child := range n.Content {
+		walkScalars(child, fn)
+	}
+}
+
+func sequenceItems(n *yaml.Node) []*yaml.Node {
+	if n == nil || n.Kind != yaml.SequenceNode {
+		return nil
+	}
+	return n.Content
+}
+
+func threeBodyRoles() []string {
+	roles := make([]string, 0, len(ThreeBody))
+	for role := range ThreeBody {
+		roles = append(roles, role)
+	}
+	sort.Strings(roles)
+	return roles
+}
+
+// commandExecutable returns the program a shell command runs, skipping `env` and VAR=value prefixes.
+func commandExecutable(command string) string {
+	for _, field := range strings.Fields(command) {
+		if field == "env" || strings.Contains(field, "=") {
+			continue
+		}
+		return ExpandPath(field)
+	}
+	return ""
+}
+
+// isHexColor accepts rrggbb with an optional leading #, matching `hyprd accent`.
+func isHexColor(value string) bool {
+	value = strings.TrimPrefix(value, "#")
+	if len(value) != 6 {
+		return false
+	}
+	for _, r := range strings.ToLower(value) {
+		if !(r >= '0' && r <= '9' || r >= 'a' && r <= 'f') {
+			return false
+		}
+	}
+	return true
+}
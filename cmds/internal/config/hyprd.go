@@ -4,8 +4,11 @@ package config
 
 import (
 	"fmt"
+	"path/filepath"
+	"slices"
 	"strconv"
 	"strings"
+	"time"
 
 	"gopkg.in/yaml.v3"
 )
@@ -16,14 +19,21 @@ import (
 
 // HyprConfig configures hyprd window and session behavior.
 type HyprConfig struct {
-	Background BackgroundConfig      `yaml:"background"`
-	Bluetooth  BluetoothConfig       `yaml:"bluetooth"`
-	Init       InitConfig            `yaml:"init"`
-	Notify     NotifyConfig          `yaml:"notify"`
-	VPN        VPNConfig             `yaml:"vpn"`
-	Windows    WindowsConfig         `yaml:"windows"`
-	Tabs       map[string]TabProfile `yaml:"tabs"`
-	Sessions   SessionsConfig        `yaml:"sessions"`
+	Background BackgroundConfig           `yaml:"background"`
+	Bluetooth  BluetoothConfig            `yaml:"bluetooth"`
+	Idle       IdleConfig                 `yaml:"idle"`
+	Lock       LockConfig                 `yaml:"lock"`
+	Init       InitConfig                 `yaml:"init"`
+	Notify     NotifyConfig               `yaml:"notify"`
+	Share      ShareConfig                `yaml:"share"`
+	VPN        VPNConfig                  `yaml:"vpn"`
+	Windows    WindowsConfig              `yaml:"windows"`
+	Tabs       map[string]TabProfile      `yaml:"tabs"`
+	Sessions   SessionsConfig             `yaml:"sessions"`
+	Templates  map[string]SessionTemplate `yaml:"templates"`
+	Projects   map[string]Project         `yaml:"projects"`
+	Activity   ActivityConfig             `yaml:"activity"`
+	Hooks      HooksConfig                `yaml:"hooks"`
 }
 
 // VPNConfig lists NetworkManager VPN profiles that can be loaded from secrets.
@@ -42,11 +52,100 @@ type VPNConnection struct {
 
 // InitConfig controls the one-time boot sequence (env setup before sessions).
 //
-// Sessions opened on boot are those with init: true in the session catalog.
+// Sessions opened on boot are those with init: true in the session catalog, unless the
+// selected boot profile lists its own.
 type InitConfig struct {
-	Workspace      int  `yaml:"workspace"`       // workspace to focus after boot
-	Lock           bool `yaml:"lock"`            // lock the session after boot completes
-	NetworkTimeout int  `yaml:"network_timeout"` // seconds to wait for network before proceeding
+	Workspace      int           `yaml:"workspace"`       // workspace to focus after boot
+	Lock           bool          `yaml:"lock"`            // lock the session after boot completes
+	NetworkTimeout int           `yaml:"network_timeout"` // seconds to wait for network before proceeding
+	Profiles       []BootProfile `yaml:"profiles"`        // first profile whose rules match wins
+}
+
+// BootProfile overrides init choices for one context (work, home, travel).
+//
+// Unset fields keep the base init, bluetooth, and background values.
+type BootProfile struct {
+	Name      string       `yaml:"name"`
+	When      ProfileMatch `yaml:"when"`
+	Sessions  []string     `yaml:"sessions"`  // init sessions; replaces the catalog's init: true flags when set
+	Workspace int          `yaml:"workspace"` // overrides init.workspace
+	Lock      *bool        `yaml:"lock"`      // overrides init.lock
+	Bluetooth string       `yaml:"bluetooth"` // device address overriding bluetooth.device; "off" skips connecting
+	VPN       []string     `yaml:"vpn"`       // vpn.connections keys brought up once the network is ready
+	Wallpaper string       `yaml:"wallpaper"` // file relative to background.video_path, pinned at boot
+}
+
+// ProfileMatch selects a boot profile automatically; every field that is set must match.
+//
+// A profile with no rules always matches, so list it last as the fallback.
+type ProfileMatch struct {
+	Hostname string   `yaml:"hostname"`
+	Days     []string `yaml:"days"`     // sun|mon|tue|wed|thu|fri|sat
+	Hours    string   `yaml:"hours"`    // "HH:MM-HH:MM" local time, as in background.playlist
+	SSID     []string `yaml:"ssid"`     // any of these Wi-Fi networks connected
+	Monitors []string `yaml:"monitors"` // every one connected, by output name (DP-1) or description substring
+}
+
+// BootContext is the machine state profile rules are matched against.
+type BootContext struct {
+	Hostname string
+	Now      time.Time
+	SSID     string   // connected Wi-Fi network; "" when wired or offline
+	Monitors []string // connected output names and descriptions
+}
+
+// Matches reports whether ctx satisfies every rule that is set.
+func (m ProfileMatch) Matches(ctx BootContext) bool {
+	if m.Hostname != "" && !strings.EqualFold(m.Hostname, ctx.Hostname) {
+		return false
+	}
+	if len(m.Days) > 0 && !slices.Contains(m.Days, Weekdays[ctx.Now.Weekday()]) {
+		return false
+	}
+	if m.Hours != "" && !inHours(m.Hours, ctx.Now) {
+		return false
+	}
+	if len(m.SSID) > 0 && !slices.Contains(m.SSID, ctx.SSID) {
+		return false
+	}
+	for _, want := range m.Monitors {
+		if !slices.ContainsFunc(ctx.Monitors, func(have string) bool { return have == want || strings.Contains(have, want) }) {
+			return false
+		}
+	}
+	return true
+}
+
+// Profile returns the named boot profile.
+func (c InitConfig) Profile(name string) (BootProfile, bool) {
+	i := slices.IndexFunc(c.Profiles, func(p BootProfile) bool { return p.Name == name })
+	if i < 0 {
+		return BootProfile{}, false
+	}
+	return c.Profiles[i], true
+}
+
+// SelectProfile returns the first profile whose rules match ctx.
+func (c InitConfig) SelectProfile(ctx BootContext) (BootProfile, bool) {
+	for _, p := range c.Profiles {
+		if p.When.Matches(ctx) {
+			return p, true
+		}
+	}
+	return BootProfile{}, false
+}
+
+// BluetoothFor applies the profile's device override to base.
+func (p BootProfile) BluetoothFor(base BluetoothConfig) BluetoothConfig {
+	switch p.Bluetooth {
+	case "":
+	case "off":
+		base.Enabled = false
+	default:
+		base.Enabled = true
+		base.Device = p.Bluetooth
+	}
+	return base
 }
 
 // BluetoothConfig controls automatic device connection at startup and unlock.
@@ -56,11 +155,14 @@ type BluetoothConfig struct {
 }
 
 // BackgroundConfig controls the mpvpaper video wallpaper.
+//
+// Wallpaper is the fallback when no playlist entry's schedule matches.
 type BackgroundConfig struct {
-	Display   string    `yaml:"display"`    // monitor name, or "auto" to use Hyprland's active output
-	VideoPath string    `yaml:"video_path"` // directory containing wallpaper videos
-	Socket    string    `yaml:"socket"`     // mpv IPC socket path
-	Wallpaper Wallpaper `yaml:"wallpaper"`
+	Display   string          `yaml:"display"`    // monitor name, or "auto" to use Hyprland's active output
+	VideoPath string          `yaml:"video_path"` // directory containing wallpaper videos
+	Socket    string          `yaml:"socket"`     // mpv IPC socket path
+	Wallpaper Wallpaper       `yaml:"wallpaper"`
+	Playlist  []PlaylistEntry `yaml:"playlist"` // first matching entry wins, so list specific schedules first
 }
 
 // Wallpaper selects a video file and tunes mpv visual properties.
@@ -72,6 +174,256 @@ type Wallpaper struct {
 	Hue        int    `yaml:"hue"`
 }
 
+// Weekdays lists playlist day names in time.Weekday order.
+var Weekdays = []string{"sun", "mon", "tue", "wed", "thu", "fri", "sat"}
+
+// PlaylistEntry is a wallpaper with an optional schedule; an entry without one always matches.
+//
+// Visual values left unset inherit from background.wallpaper.
+type PlaylistEntry struct {
+	File       string   `yaml:"file"` // filename relative to VideoPath
+	Brightness *int     `yaml:"brightness"`
+	Contrast   *int     `yaml:"contrast"`
+	Saturation *int     `yaml:"saturation"`
+	Hue        *int     `yaml:"hue"`
+	Hours      string   `yaml:"hours"`    // "HH:MM-HH:MM" local time; wraps past midnight when end <= start
+	Days       []string `yaml:"days"`     // sun|mon|tue|wed|thu|fri|sat; empty means every day
+	Sessions   []string `yaml:"sessions"` // match while the focused workspace runs one of these sessions
+}
+
+// Resolve returns the entry as a Wallpaper, filling unset visual values from base.
+func (e PlaylistEntry) Resolve(base Wallpaper) Wallpaper {
+	w := base
+	w.File = e.File
+	if e.Brightness != nil {
+		w.Brightness = *e.Brightness
+	}
+	if e.Contrast != nil {
+		w.Contrast = *e.Contrast
+	}
+	if e.Saturation != nil {
+		w.Saturation = *e.Saturation
+	}
+	if e.Hue != nil {
+		w.Hue = *e.Hue
+	}
+	return w
+}
+
+// Matches reports whether now and the focused workspace's session satisfy every schedule field that is set.
+func (e PlaylistEntry) Matches(now time.Time, session string) bool {
+	if len(e.Days) > 0 && !slices.Contains(e.Days, Weekdays[now.Weekday()]) {
+		return false
+	}
+	if len(e.Sessions) > 0 && !slices.Contains(e.Sessions, session) {
+		return false
+	}
+	return e.Hours == "" || inHours(e.Hours, now)
+}
+
+// inHours reports whether now falls in an "HH:MM-HH:MM" window; invalid windows never match.
+func inHours(hours string, now time.Time) bool {
+	start, end, err := ParseHours(hours)
+	if err != nil {
+		return false
+	}
+	minute := now.Hour()*60 + now.Minute()
+	if end <= start {
+		return minute >= start || minute < end
+	}
+	return minute >= start && minute < end
+}
+
+// Scheduled returns the first playlist entry matching now and session, or the base wallpaper.
+func (c BackgroundConfig) Scheduled(now time.Time, session string) Wallpaper {
+	for _, entry := range c.Playlist {
+		if entry.Matches(now, session) {
+			return entry.Resolve(c.Wallpaper)
+		}
+	}
+	return c.Wallpaper
+}
+
+// ParseHours parses "HH:MM-HH:MM" into start and end minutes past midnight.
+func ParseHours(hours string) (start, end int, err error) {
+	from, to, ok := strings.Cut(hours, "-")
+	if !ok {
+		return 0, 0, fmt.Errorf("hours %q: want HH:MM-HH:MM", hours)
+	}
+	if start, err = parseClock(strings.TrimSpace(from)); err != nil {
+		return 0, 0, fmt.Errorf("hours %q: %w", hours, err)
+	}
+	if end, err = parseClock(strings.TrimSpace(to)); err != nil {
+		return 0, 0, fmt.Errorf("hours %q: %w", hours, err)
+	}
+	return start, end, nil
+}
+
+func parseClock(value string) (int, error) {
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time %q", value)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// ╭──────────────────────────────────────────────────────────────────────────────╮
+// │ lock hooks                                                                   │
+// ╰──────────────────────────────────────────────────────────────────────────────╯
+
+// LockBuiltins lists the built-in lock side effects, each of which lock.disable can turn off.
+var LockBuiltins = []string{"glava", "notifications", "music", "eww", "startup", "ssh"}
+
+// LockConfig adds hooks around pseudo and full locks and disables built-in side effects.
+type LockConfig struct {
+	Disable []string  `yaml:"disable"` // glava|notifications|music|eww|startup|ssh
+	Hooks   LockHooks `yaml:"hooks"`
+}
+
+// LockHooks run after the built-ins of the same phase.
+//
+// A full lock from an unlocked desktop runs pre_pseudo, pre_lock, then post_unlock and
+// post_pseudo once hyprlock authenticates; a pseudo-lock only runs the pseudo phases.
+type LockHooks struct {
+	PrePseudo  []LockHook `yaml:"pre_pseudo"`  // entering the blackout
+	PreLock    []LockHook `yaml:"pre_lock"`    // before hyprlock starts
+	PostUnlock []LockHook `yaml:"post_unlock"` // after hyprlock exits
+	PostPseudo []LockHook `yaml:"post_pseudo"` // leaving the blackout
+}
+
+// LockHook runs a shell command or a hyprd verb; set exactly one.
+//
+// A run command gets {"event": <phase>, "time": ...} as JSON on stdin and HYPRD_EVENT=<phase>
+// in its environment, and is killed with its whole process group on timeout.
+type LockHook struct {
+	Run     string `yaml:"run"`     // sh -c command
+	Hyprd   string `yaml:"hyprd"`   // daemon verb and args, e.g. "share off"
+	Timeout int    `yaml:"timeout"` // seconds; 0 uses 5
+}
+
+// Disabled reports whether a built-in lock side effect is turned off.
+func (c LockConfig) Disabled(builtin string) bool {
+	return slices.Contains(c.Disable, builtin)
+}
+
+// ╭──────────────────────────────────────────────────────────────────────────────╮
+// │ idle policy                                                                  │
+// ╰──────────────────────────────────────────────────────────────────────────────╯
+
+// IdleActions lists the actions an idle stage can run.
+var IdleActions = []string{"pseudo", "lock", "dpms", "suspend"}
+
+// IdleInhibitors lists the conditions that can veto an idle stage.
+var IdleInhibitors = []string{"share", "music", "fullscreen", "alarm"}
+
+// IdleConfig drives the idle stages hyprd runs after hypridle reports `hyprd idle start`.
+type IdleConfig struct {
+	Stages     []IdleStage `yaml:"stages"`
+	Recheck    int         `yaml:"recheck"`    // seconds between inhibitor checks while a stage is vetoed
+	AlarmLead  int         `yaml:"alarm_lead"` // minutes before an ewwd timer/alarm fires that "alarm" inhibits
+	Fullscreen []string    `yaml:"fullscreen"` // classes whose fullscreen windows inhibit; empty means any
+}
+
+// IdleStage runs Action After seconds past the idle signal unless one of Inhibit is active.
+type IdleStage struct {
+	After   int      `yaml:"after"`
+	Action  string   `yaml:"action"`  // pseudo|lock|dpms|suspend
+	Inhibit []string `yaml:"inhibit"` // share|music|fullscreen|alarm
+}
+
+// WithDefaults fills recheck and alarm lead, and falls back to the pseudo -> lock -> dpms escalation.
+func (c IdleConfig) WithDefaults() IdleConfig {
+	if c.Recheck <= 0 {
+		c.Recheck = 30
+	}
+	if c.AlarmLead <= 0 {
+		c.AlarmLead = 10
+	}
+	if len(c.Stages) == 0 {
+		c.Stages = []IdleStage{
+			{After: 0, Action: "pseudo", Inhibit: []string{"share", "music", "fullscreen"}},
+			{After: 780, Action: "lock", Inhibit: []string{"share", "fullscreen"}},
+			{After: 840, Action: "dpms", Inhibit: []string{"share", "fullscreen"}},
+		}
+	}
+	return c
+}
+
+// ╭──────────────────────────────────────────────────────────────────────────────╮
+// │ activity                                                                     │
+// ╰──────────────────────────────────────────────────────────────────────────────╯
+
+// ActivityConfig opts in to recording window focus intervals for `hyprd stats`.
+//
+// Intervals stay in a local SQLite file; nothing is recorded while away or locked.
+type ActivityConfig struct {
+	Enabled  bool     `yaml:"enabled"`
+	Database string   `yaml:"database"` // SQLite path, may start with "~/"; default $XDG_STATE_HOME/hyprd/activity.db
+	Ignore   []string `yaml:"ignore"`   // window classes never recorded (case-insensitive)
+}
+
+// Ignores reports whether focus on a window of class should go unrecorded.
+func (c ActivityConfig) Ignores(class string) bool {
+	return containsFold(c.Ignore, class)
+}
+
+// ╭──────────────────────────────────────────────────────────────────────────────╮
+// │ event hooks                                                                  │
+// ╰──────────────────────────────────────────────────────────────────────────────╯
+
+// HookEvents lists the daemon events user hooks can run on.
+var HookEvents = []string{
+	"workspace", "window_open", "window_close",
+	"lock", "unlock", "share_on", "share_off", "session_open",
+}
+
+// HooksConfig runs shell commands on daemon events. Each command gets the event as JSON on
+// stdin and as HYPRD_* environment variables.
+type HooksConfig struct {
+	Concurrency int                    `yaml:"concurrency"` // hooks running at once; 0 uses 4
+	Timeout     int                    `yaml:"timeout"`     // default seconds per hook; 0 uses 10
+	Events      map[string][]EventHook `yaml:"events"`      // event name -> hooks, run in parallel
+}
+
+// EventHook runs Run when its event fires and every set filter matches.
+type EventHook struct {
+	Run       string   `yaml:"run"`       // sh -c command
+	Class     []string `yaml:"class"`     // window classes (case-insensitive); window events only
+	Workspace []int    `yaml:"workspace"` // workspace ids the event happened on
+	Timeout   int      `yaml:"timeout"`   // seconds; 0 uses hooks.timeout
+}
+
+// Matches reports whether an event on workspace ws, for a window of class, passes the filters.
+// A class filter never matches events without a window.
+func (h EventHook) Matches(class string, ws int) bool {
+	if len(h.Class) > 0 && (class == "" || !containsFold(h.Class, class)) {
+		return false
+	}
+	return len(h.Workspace) == 0 || slices.Contains(h.Workspace, ws)
+}
+
+// WithDefaults fills concurrency and the default timeout.
+func (c HooksConfig) WithDefaults() HooksConfig {
+	if c.Concurrency <= 0 {
+		c.Concurrency = 4
+	}
+	if c.Timeout <= 0 {
+		c.Timeout = 10
+	}
+	return c
+}
+
+// Matching returns the hooks for event whose filters pass, in config order.
+func (c HooksConfig) Matching(event, class string, ws int) []EventHook {
+	var out []EventHook
+	for _, h := range c.Events[event] {
+		if h.Matches(class, ws) {
+			out = append(out, h)
+		}
+	}
+	return out
+}
+
 // ╭──────────────────────────────────────────────────────────────────────────────╮
 // │ windows / layout                                                             │
 // ╰──────────────────────────────────────────────────────────────────────────────╯
@@ -163,6 +515,47 @@ func (c *NotifyConfig) ResolveEvent(name string) ResolvedStyle {
 	}
 }
 
+// ╭──────────────────────────────────────────────────────────────────────────────╮
+// │ screen share                                                                 │
+// ╰──────────────────────────────────────────────────────────────────────────────╯
+
+// ShareNotifyModes lists how notifications are handled while sharing.
+var ShareNotifyModes = []string{"mute", "downgrade"}
+
+// ShareConfig controls the privacy rules applied by screen-share mode.
+//
+// mute pauses dunst so everything queues until share off; downgrade keeps dunst live but
+// shows every notification silent, low urgency, and without its text.
+type ShareConfig struct {
+	Workspace string   `yaml:"workspace"` // special workspace that holds concealed windows
+	Classes   []string `yaml:"classes"`   // window classes to conceal (case-insensitive)
+	Titles    []string `yaml:"titles"`    // title substrings to conceal (case-insensitive)
+	Accent    string   `yaml:"accent"`    // active border while sharing (#rrggbb); empty keeps the accent
+	Notify    string   `yaml:"notify"`    // mute|downgrade
+}
+
+// WithDefaults fills the privacy workspace name and the mute notification mode.
+func (c ShareConfig) WithDefaults() ShareConfig {
+	if c.Workspace == "" {
+		c.Workspace = "privacy"
+	}
+	if !slices.Contains(ShareNotifyModes, c.Notify) {
+		c.Notify = "mute"
+	}
+	return c
+}
+
+// Conceals reports whether a window with class and title matches a privacy rule.
+func (c ShareConfig) Conceals(class, title string) bool {
+	if slices.ContainsFunc(c.Classes, func(rule string) bool { return strings.EqualFold(rule, class) }) {
+		return true
+	}
+	title = strings.ToLower(title)
+	return slices.ContainsFunc(c.Titles, func(rule string) bool {
+		return rule != "" && strings.Contains(title, strings.ToLower(rule))
+	})
+}
+
 // ╭──────────────────────────────────────────────────────────────────────────────╮
 // │ windows / workspaces                                                         │
 // ╰──────────────────────────────────────────────────────────────────────────────╯
@@ -172,6 +565,8 @@ type WindowsConfig struct {
 	Split   SplitConfig   `yaml:"split"`
 	GapsOut GapsOutConfig `yaml:"gaps_out"`
 	Monocle MonocleConfig `yaml:"monocle"`
+	PiP     PiPConfig     `yaml:"pip"`
+	Swallow SwallowConfig `yaml:"swallow"`
 }
 
 // GapsOutConfig stores normal and screen-share outer gaps in Hyprland's order:
@@ -245,6 +640,11 @@ func (g OuterGaps) OptionValue() string {
 	return fmt.Sprintf("%d %d %d %d", g.values[0], g.values[1], g.values[2], g.values[3])
 }
 
+// Edges returns the gaps as top, right, bottom, left.
+func (g OuterGaps) Edges() (top, right, bottom, left int) {
+	return g.values[0], g.values[1], g.values[2], g.values[3]
+}
+
 // MonocleConfig controls single-window monocle mode sizing and offset (px).
 type MonocleConfig struct {
 	Width   int `yaml:"width"`
@@ -253,6 +653,65 @@ type MonocleConfig struct {
 	OffsetY int `yaml:"offset_y"`
 }
 
+// PiPCorners lists the picture-in-picture corners in clockwise cycle order.
+var PiPCorners = []string{"nw", "ne", "se", "sw"}
+
+// SwallowConfig hides a terminal while a window it launched (zathura, mpv, imv) is open.
+//
+// A child is swallowed when its class is allowed and one of its /proc ancestors is a
+// terminal window's process on the same workspace.
+type SwallowConfig struct {
+	Enabled   bool     `yaml:"enabled"`
+	Terminals []string `yaml:"terminals"` // parent window classes; default kitty
+	Classes   []string `yaml:"classes"`   // child classes to swallow; empty allows any not excluded
+	Exclude   []string `yaml:"exclude"`   // child classes never swallowed
+}
+
+// WithDefaults treats kitty as the only terminal when none are listed.
+func (c SwallowConfig) WithDefaults() SwallowConfig {
+	if len(c.Terminals) == 0 {
+		c.Terminals = []string{"kitty"}
+	}
+	return c
+}
+
+// IsTerminal reports whether class is a configured parent terminal.
+func (c SwallowConfig) IsTerminal(class string) bool {
+	return containsFold(c.Terminals, class)
+}
+
+// Swallows reports whether a child window of class may hide its terminal. Terminals
+// launched from terminals are never swallowed.
+func (c SwallowConfig) Swallows(class string) bool {
+	if class == "" || c.IsTerminal(class) || containsFold(c.Exclude, class) {
+		return false
+	}
+	return len(c.Classes) == 0 || containsFold(c.Classes, class)
+}
+
+func containsFold(list []string, value string) bool {
+	return slices.ContainsFunc(list, func(item string) bool { return strings.EqualFold(item, value) })
+}
+
+// PiPConfig controls picture-in-picture window size (px) and its default corner.
+type PiPConfig struct {
+	Width  int    `yaml:"width"`
+	Height int    `yaml:"height"`
+	Corner string `yaml:"corner"` // nw|ne|se|sw; used when a window first enters pip
+	Margin int    `yaml:"margin"` // extra inset (px) inside gaps_out
+}
+
+// WithDefaults fills omitted size and corner values with a 16:9 bottom-right window.
+func (c PiPConfig) WithDefaults() PiPConfig {
+	if c.Width <= 0 || c.Height <= 0 {
+		c.Width, c.Height = 640, 360
+	}
+	if !slices.Contains(PiPCorners, c.Corner) {
+		c.Corner = "se"
+	}
+	return c
+}
+
 // ╭──────────────────────────────────────────────────────────────────────────────╮
 // │ kitty tab profiles                                                           │
 // ╰──────────────────────────────────────────────────────────────────────────────╯
@@ -271,7 +730,7 @@ type TabDef struct {
 	Title       string     `yaml:"title"`
 	Command     string     `yaml:"command"`
 	CWD         string     `yaml:"cwd"`         // may contain "~/"
-	CWDResolve  string     `yaml:"cwd_resolve"` // e.g. "git-root"
+	CWDResolve  string     `yaml:"cwd_resolve"` // "recent-git" or empty
 	Requires    string     `yaml:"requires"`    // must exist in CWD or tab is skipped
 	Executables []string   `yaml:"executables"` // commands required on a host before launching
 	FocusPane   int        `yaml:"focus_pane"`  // zero-based pane to focus after spawn; defaults to 0
@@ -316,6 +775,10 @@ var ThreeBody = map[string]ThreeBodyWindow{
 	"browser": {Class: "firefox-developer-edition", Command: "hyprd browser launch"},
 }
 
+// LayoutVerbs are the `hyprd layout` subcommands. Layout.Execute matches them before session
+// names, so no session may take one as its name; keep the two in step.
+var LayoutVerbs = []string{"list", "set", "save", "close", "new"}
+
 // SessionsConfig stores runtime-flat sessions keyed by name, while YAML groups them by workspace number first.
 type SessionsConfig map[string]Session
 
@@ -346,10 +809,10 @@ func (s *SessionsConfig) UnmarshalYAML(value *yaml.Node) error {
 
 		ws, err := strconv.Atoi(strings.TrimSpace(wsNode.Value))
 		if err != nil {
-			return fmt.Errorf("sessions: invalid workspace %q", wsNode.Value)
+			return fmt.Errorf("line %d: sessions: invalid workspace %q", wsNode.Line, wsNode.Value)
 		}
 		if groupNode.Kind != yaml.MappingNode {
-			return fmt.Errorf("sessions[%d] must be a mapping of session name -> config", ws)
+			return fmt.Errorf("line %d: sessions[%d] must be a mapping of session name -> config", groupNode.Line, ws)
 		}
 
 		for j := 0; j < len(groupNode.Content); j += 2 {
@@ -357,10 +820,10 @@ func (s *SessionsConfig) UnmarshalYAML(value *yaml.Node) error {
 			sessionNode := groupNode.Content[j+1]
 			name := strings.TrimSpace(nameNode.Value)
 			if name == "" {
-				return fmt.Errorf("sessions[%d]: session name cannot be empty", ws)
+				return fmt.Errorf("line %d: sessions[%d]: session name cannot be empty", nameNode.Line, ws)
 			}
 			if _, exists := out[name]; exists {
-				return fmt.Errorf("sessions: duplicate session name %q", name)
+				return fmt.Errorf("line %d: sessions: duplicate session name %q", nameNode.Line, name)
 			}
 
 			var session Session
@@ -400,6 +863,19 @@ type Session struct {
 	Command   string            `yaml:"command" json:"command"` // single-window sessions (no three-body)
 	Class     string            `yaml:"class" json:"class"`     // explicit window class for single-command sessions
 	Monocle   bool              `yaml:"monocle" json:"monocle"`
+	Requires  SessionRequires   `yaml:"requires" json:"requires"`
+}
+
+// SessionRequires lists prerequisites checked before a session spawns any windows.
+type SessionRequires struct {
+	VPN   []string `yaml:"vpn,omitempty" json:"vpn,omitempty"`     // vpn.connections keys brought up if inactive
+	Reach []string `yaml:"reach,omitempty" json:"reach,omitempty"` // host:port targets that must accept TCP
+	Env   []string `yaml:"env,omitempty" json:"env,omitempty"`     // environment variables that must be set
+}
+
+// IsZero reports whether the session has no prerequisites.
+func (r SessionRequires) IsZero() bool {
+	return len(r.VPN) == 0 && len(r.Reach) == 0 && len(r.Env) == 0
 }
 
 // SessionLayout optionally pins initial tiled roles after all session windows map.
@@ -443,6 +919,31 @@ type BrowserGroup struct {
 	URLs      []string `yaml:"urls" json:"urls"`
 }
 
+// ╭──────────────────────────────────────────────────────────────────────────────╮
+// │ projects                                                                     │
+// ╰──────────────────────────────────────────────────────────────────────────────╯
+
+// Project is a named working tree that `hyprd project open` lays out on a free workspace.
+//
+// The session comes from Template (editor + agents when unset) with ${project} set to Root;
+// Tabs, Browser, and VPN override or extend what the template declares.
+type Project struct {
+	Root     string            `yaml:"root" json:"root"`                   // may start with "~/"; must be under $HOME
+	Template string            `yaml:"template" json:"template,omitempty"` // session template to render
+	Tabs     string            `yaml:"tabs" json:"tabs,omitempty"`         // tab profile for the editor window
+	Browser  string            `yaml:"browser" json:"browser,omitempty"`   // browser snapshot restored on open
+	VPN      []string          `yaml:"vpn" json:"vpn,omitempty"`           // vpn.connections keys brought up first
+	Vars     map[string]string `yaml:"vars" json:"vars,omitempty"`         // template variables
+}
+
+// Dir returns the cleaned project root with "~/" expanded, as workspace project paths store it.
+func (p Project) Dir() string {
+	if p.Root == "" {
+		return ""
+	}
+	return filepath.Clean(ExpandPath(p.Root))
+}
+
 // ╭──────────────────────────────────────────────────────────────────────────────╮
 // │ methods                                                                      │
 // ╰──────────────────────────────────────────────────────────────────────────────╯
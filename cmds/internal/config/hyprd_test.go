@@ -0,0 +1,30 @@
+package config
+
+import "testing"
+
+func TestEventHookMatches(t *testing.T) {
+	tests := []struct {
+		name  string
+		hook  EventHook
+		class string
+		ws    int
+		want  bool
+	}{
+		{"no filters", EventHook{}, "", 4, true},
+		{"class match", EventHook{Class: []string{"firefox"}}, "firefox", 1, true},
+		{"class case-insensitive", EventHook{Class: []string{"Firefox"}}, "firefox", 1, true},
+		{"class mismatch", EventHook{Class: []string{"firefox"}}, "kitty", 1, false},
+		{"class filter without window", EventHook{Class: []string{"firefox"}}, "", 1, false},
+		{"workspace match", EventHook{Workspace: []int{2, 3}}, "", 3, true},
+		{"workspace mismatch", EventHook{Workspace: []int{2, 3}}, "kitty", 5, false},
+		{"both match", EventHook{Class: []string{"kitty"}, Workspace: []int{5}}, "kitty", 5, true},
+		{"class ok, workspace not", EventHook{Class: []string{"kitty"}, Workspace: []int{5}}, "kitty", 6, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.hook.Matches(tt.class, tt.ws); got != tt.want {
+				t.Errorf("Matches(%q, %d) = %v, want %v", tt.class, tt.ws, got, tt.want)
+			}
+		})
+	}
+}
@@ -35,19 +35,40 @@ func LoadEww() EwwConfig {
 // LoadHypr loads hyprd config from $HOME/dotfiles/cmds/config/hyprd.yaml.
 //
 // Most values must be set in the YAML; browser snapshot entries have restore defaults.
+// Notify style colours may name theme.yaml palette colours; they resolve to hex here.
 // Sound and app maps are lowercased for case-insensitive libnotify matching.
 func LoadHypr() HyprConfig {
 	var cfg HyprConfig
 	if err := loadYAMLFile(ConfigPath("hyprd"), &cfg); err != nil {
 		logConfigError("hyprd", err)
 	}
-	warnMissing(&cfg)
+	normalizeHypr(&cfg)
+	return cfg
+}
+
+// HyprYAML returns the hyprd.yaml LoadHypr reads, e.g. to record it with a trace.
+func HyprYAML() ([]byte, error) {
+	return readConfigFile(ConfigPath("hyprd"))
+}
+
+// ParseHypr decodes hyprd YAML such as HyprYAML returns, normalized the same way as LoadHypr.
+func ParseHypr(data []byte) (HyprConfig, error) {
+	var cfg HyprConfig
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return cfg, err
+	}
+	normalizeHypr(&cfg)
+	return cfg, nil
+}
+
+func normalizeHypr(cfg *HyprConfig) {
+	warnMissing(cfg)
+	resolveStyleColors(&cfg.Notify)
 	cfg.Notify.UrgencySounds = lowercaseKeys(cfg.Notify.UrgencySounds)
 	cfg.Notify.AppSounds = lowercaseKeys(cfg.Notify.AppSounds)
 	cfg.Notify.ActionFocusApps = lowercaseKeys(cfg.Notify.ActionFocusApps)
 	cfg.Notify.SilentApps = lowercaseSlice(cfg.Notify.SilentApps)
 	cfg.Notify.KittySilentPatterns = lowercaseSlice(cfg.Notify.KittySilentPatterns)
-	return cfg
 }
 
 // LoadNewtab returns newtab defaults (no YAML; Firefox DB is resolved at runtime).
@@ -56,15 +77,19 @@ func LoadNewtab() NewtabConfig {
 }
 
 func loadYAMLFile(relPath string, dst any) error {
-	home, err := os.UserHomeDir()
+	data, err := readConfigFile(relPath)
 	if err != nil {
 		return err
 	}
-	data, err := os.ReadFile(filepath.Join(home, relPath))
+	return yaml.Unmarshal(data, dst)
+}
+
+func readConfigFile(relPath string) ([]byte, error) {
+	home, err := os.UserHomeDir()
 	if err != nil {
-		return err
+		return nil, err
 	}
-	return yaml.Unmarshal(data, dst)
+	return os.ReadFile(filepath.Join(home, relPath))
 }
 
 func logConfigError(section string, err error) {
@@ -20,6 +20,13 @@ func LocalConfigPath(name string) string {
 	return filepath.Join(configsDir, name+".local.yaml")
 }
 
+// DropInPath returns the $HOME-relative path to a reviewable config fragment under <name>.d/.
+//
+// Fragments are not loaded; they are staged for a human to merge into the main config.
+func DropInPath(name, fragment string) string {
+	return filepath.Join(configsDir, name+".d", fragment+".yaml")
+}
+
 // ExpandPath resolves a leading "~/" against the current user's home directory.
 func ExpandPath(path string) string {
 	if strings.HasPrefix(path, "~/") {
@@ -0,0 +1,262 @@
+package config
+
+// template.go renders parameterized session templates into concrete sessions.
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+var templateVar = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)\}`)
+
+// SessionTemplate is a session block whose command, project, browser URLs, reach targets, and
+// tab profile CWDs may reference ${var} placeholders, filled by `hyprd layout new --var k=v`.
+//
+// Vars holds defaults; a variable with an empty default must be passed. ${name} and
+// ${workspace} are always defined from the rendered session.
+type SessionTemplate struct {
+	Vars      map[string]string `yaml:"vars"`
+	Workspace int               `yaml:"workspace"` // default workspace when --workspace is omitted
+	Session   `yaml:",inline"`
+}
+
+// RenderedSession is a template expanded for one session, plus any tab profiles cloned
+// because their CWDs referenced template variables.
+type RenderedSession struct {
+	Session Session
+	Tabs    map[string]TabProfile
+}
+
+// RenderTemplate expands template tmpl into a session called name on workspace ws (0 uses the
+// template default). Tab profiles with placeholder CWDs are cloned as "<name>.<profile>".
+func (c *HyprConfig) RenderTemplate(tmpl, name string, ws int, vars map[string]string) (RenderedSession, error) {
+	t, ok := c.Templates[tmpl]
+	if !ok {
+		return RenderedSession{}, fmt.Errorf("unknown template: %s", tmpl)
+	}
+	if _, exists := c.Sessions[name]; exists {
+		return RenderedSession{}, fmt.Errorf("session %q already exists", name)
+	}
+	return c.renderSession(t, fmt.Sprintf("template %q", tmpl), name, ws, vars)
+}
+
+// defaultProjectTemplate lays out projects without a template like most catalog sessions.
+var defaultProjectTemplate = SessionTemplate{Session: Session{Body: []string{"editor", "agents"}}}
+
+// RenderProject expands project name into a session of the same name on workspace ws.
+//
+// Variables layer as template defaults, then the project's vars, then vars; ${project} is
+// always the project root. The project's tab profile, browser snapshot, and VPNs are applied
+// before rendering so cloned tab profiles pick them up.
+func (c *HyprConfig) RenderProject(name string, ws int, vars map[string]string) (RenderedSession, error) {
+	p, ok := c.Projects[name]
+	if !ok {
+		return RenderedSession{}, fmt.Errorf("unknown project: %s", name)
+	}
+	t := defaultProjectTemplate
+	if p.Template != "" {
+		if t, ok = c.Templates[p.Template]; !ok {
+			return RenderedSession{}, fmt.Errorf("project %q: unknown template: %s", name, p.Template)
+		}
+	}
+	t.Vars = maps.Clone(t.Vars)
+	if t.Vars == nil {
+		t.Vars = make(map[string]string)
+	}
+	maps.Copy(t.Vars, p.Vars)
+	maps.Copy(t.Vars, vars)
+	t.Vars["project"] = p.Dir()
+
+	t.Project = "${project}"
+	if p.Tabs != "" {
+		t.Tabs = maps.Clone(t.Tabs)
+		if t.Tabs == nil {
+			t.Tabs = make(map[string]string)
+		}
+		t.Tabs["editor"] = p.Tabs
+	}
+	if p.Browser != "" {
+		t.Browser = BrowserConfig{Snapshot: p.Browser}
+		if p.Template == "" {
+			t.Body = []string{"editor", "browser", "agents"}
+		}
+	}
+	if len(p.VPN) > 0 {
+		t.Requires.VPN = append(slices.Clone(t.Requires.VPN), p.VPN...)
+	}
+	return c.renderSession(t, fmt.Sprintf("project %q", name), name, ws, nil)
+}
+
+// renderSession expands t for one session; label names the source in errors.
+func (c *HyprConfig) renderSession(t SessionTemplate, label, name string, ws int, vars map[string]string) (RenderedSession, error) {
+	if ws == 0 {
+		ws = t.Workspace
+	}
+	if ws <= 0 {
+		return RenderedSession{}, fmt.Errorf("%s has no default workspace; pass --workspace", label)
+	}
+
+	values := maps.Clone(t.Vars)
+	if values == nil {
+		values = make(map[string]string)
+	}
+	maps.Copy(values, vars)
+	values["name"] = name
+	values["workspace"] = fmt.Sprint(ws)
+	var missing []string
+	for key, value := range values {
+		if value == "" {
+			missing = append(missing, key)
+		}
+	}
+	if len(missing) > 0 {
+		slices.Sort(missing)
+		return RenderedSession{}, fmt.Errorf("%s requires --var %s", label, strings.Join(missing, ", --var "))
+	}
+
+	r := &templateRenderer{values: values}
+	r.resolveVars() // defaults may build on other vars, e.g. repo: ${name}
+	s := t.Session
+	s.Name = name
+	s.Workspace = ws
+	s.Init = false
+	s.Command = r.expand(s.Command)
+	s.Project = r.project(s.Project)
+	s.Body = slices.Clone(s.Body)
+	s.Requires.Reach = r.expandAll(s.Requires.Reach)
+	s.Browser.URLs = r.expandAll(s.Browser.URLs)
+	s.Browser.Pinned = r.expandAll(s.Browser.Pinned)
+	s.Browser.Groups = slices.Clone(s.Browser.Groups)
+	for i := range s.Browser.Groups {
+		s.Browser.Groups[i].URLs = r.expandAll(s.Browser.Groups[i].URLs)
+	}
+
+	rendered := RenderedSession{Tabs: make(map[string]TabProfile)}
+	s.Tabs = maps.Clone(s.Tabs)
+	for role, profileName := range s.Tabs {
+		profile, ok := c.Tabs[profileName]
+		if !ok || !profileUsesVars(profile) {
+			continue
+		}
+		clone := name + "." + profileName
+		rendered.Tabs[clone] = r.profile(profile)
+		s.Tabs[role] = clone
+	}
+	if r.err != nil {
+		return RenderedSession{}, fmt.Errorf("%s: %w", label, r.err)
+	}
+	rendered.Session = s
+	return rendered, nil
+}
+
+type templateRenderer struct {
+	values map[string]string
+	err    error
+}
+
+// resolveVars expands references between vars in dependency order, so a default can build on
+// any other var whatever the map order; a cycle is recorded as an error naming its path.
+func (r *templateRenderer) resolveVars() {
+	done := make(map[string]bool, len(r.values))
+	var path []string // vars being resolved, outermost first
+	var resolve func(key string)
+	resolve = func(key string) {
+		if done[key] {
+			return
+		}
+		if i := slices.Index(path, key); i >= 0 {
+			if r.err == nil {
+				cycle := append(slices.Clone(path[i:]), key)
+				r.err = fmt.Errorf("variable cycle: ${%s}", strings.Join(cycle, "} -> ${"))
+			}
+			return
+		}
+		path = append(path, key)
+		for _, match := range templateVar.FindAllStringSubmatch(r.values[key], -1) {
+			if _, ok := r.values[match[1]]; ok {
+				resolve(match[1])
+			}
+		}
+		path = path[:len(path)-1]
+		r.values[key] = r.expand(r.values[key])
+		done[key] = true
+	}
+	for _, key := range slices.Sorted(maps.Keys(r.values)) {
+		resolve(key)
+	}
+}
+
+// expand substitutes ${var} placeholders, recording the first undefined variable.
+func (r *templateRenderer) expand(value string) string {
+	return templateVar.ReplaceAllStringFunc(value, func(match string) string {
+		key := templateVar.FindStringSubmatch(match)[1]
+		v, ok := r.values[key]
+		if !ok && r.err == nil {
+			r.err = fmt.Errorf("undefined variable ${%s}", key)
+		}
+		return v
+	})
+}
+
+func (r *templateRenderer) expandAll(values []string) []string {
+	if values == nil {
+		return nil
+	}
+	out := make([]string, len(values))
+	for i, v := range values {
+		out[i] = r.expand(v)
+	}
+	return out
+}
+
+// project expands the project path and makes it $HOME-relative, as sessions store it.
+func (r *templateRenderer) project(value string) string {
+	value = ExpandPath(r.expand(value))
+	if value == "" || !filepath.IsAbs(value) {
+		return value
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return value
+	}
+	rel, err := filepath.Rel(home, value)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, "../") {
+		if r.err == nil {
+			r.err = fmt.Errorf("project %s is outside $HOME", value)
+		}
+		return value
+	}
+	return rel
+}
+
+// profile clones a tab profile with its tab and pane CWDs expanded.
+func (r *templateRenderer) profile(p TabProfile) TabProfile {
+	p.Tabs = slices.Clone(p.Tabs)
+	for i := range p.Tabs {
+		p.Tabs[i].CWD = r.expand(p.Tabs[i].CWD)
+		p.Tabs[i].Panes = slices.Clone(p.Tabs[i].Panes)
+		for j := range p.Tabs[i].Panes {
+			p.Tabs[i].Panes[j].CWD = r.expand(p.Tabs[i].Panes[j].CWD)
+		}
+	}
+	return p
+}
+
+func profileUsesVars(p TabProfile) bool {
+	for _, tab := range p.Tabs {
+		if templateVar.MatchString(tab.CWD) {
+			return true
+		}
+		for _, pane := range tab.Panes {
+			if templateVar.MatchString(pane.CWD) {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,61 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRenderTemplate(t *testing.T) {
+	tests := []struct {
+		name    string
+		vars    map[string]string // template defaults
+		command string
+		want    string
+		wantErr string
+	}{
+		{
+			name:    "chained default",
+			vars:    map[string]string{"url": "https://github.com/cogikyo/${repo}", "repo": "${name}-cli"},
+			command: "open ${url} on ${workspace}",
+			want:    "open https://github.com/cogikyo/demo-cli on 3",
+		},
+		{
+			name:    "undefined var",
+			command: "echo ${nope}",
+			wantErr: "undefined variable ${nope}",
+		},
+		{
+			name:    "self reference",
+			vars:    map[string]string{"a": "x${a}"},
+			command: "${a}",
+			wantErr: "variable cycle: ${a} -> ${a}",
+		},
+		{
+			name:    "two-var cycle",
+			vars:    map[string]string{"a": "${b}", "b": "${a}"},
+			command: "${a}",
+			wantErr: "variable cycle: ${a} -> ${b} -> ${a}",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tmpl := SessionTemplate{Vars: tt.vars, Workspace: 3}
+			tmpl.Command = tt.command
+			cfg := &HyprConfig{Templates: map[string]SessionTemplate{"t": tmpl}}
+
+			got, err := cfg.RenderTemplate("t", "demo", 0, nil)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("err = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Session.Command != tt.want {
+				t.Errorf("command = %q, want %q", got.Session.Command, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,221 @@
+package config
+
+// theme.go loads the shared colour palettes in theme.yaml and resolves colour references.
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ThemeConfig is cmds/config/theme.yaml: named palettes that `dctl theme apply` renders
+// into kitty, dunst, eww, and Hyprland config, and that hyprd reads for its own colours.
+type ThemeConfig struct {
+	Default  string             `yaml:"default"` // palette used until `dctl theme apply <name>` picks another
+	Palettes map[string]Palette `yaml:"palettes"`
+}
+
+// Palette is one named colour set.
+//
+// Colors keeps file order so rendered palettes (eww's _palette.scss) read like the source.
+// Roles name the colours other tools care about by purpose, e.g. accent: orn-3.
+type Palette struct {
+	Colors Swatches          `yaml:"colors"`
+	Roles  map[string]string `yaml:"roles"`
+}
+
+// Swatch is a named colour, Hex as "rrggbb".
+type Swatch struct {
+	Name string
+	Hex  string
+}
+
+// Swatches is an ordered name -> #rrggbb mapping.
+type Swatches []Swatch
+
+func (s *Swatches) UnmarshalYAML(value *yaml.Node) error {
+	if value.Kind != yaml.MappingNode {
+		return fmt.Errorf("line %d: colors must be a mapping of name: \"#rrggbb\"", value.Line)
+	}
+	out := make(Swatches, 0, len(value.Content)/2)
+	for i := 0; i+1 < len(value.Content); i += 2 {
+		name, hex := value.Content[i].Value, value.Content[i+1].Value
+		if !isHexColor(hex) {
+			return fmt.Errorf("line %d: color %s: want #rrggbb, got %q", value.Content[i+1].Line, name, hex)
+		}
+		out = append(out, Swatch{Name: name, Hex: strings.ToLower(strings.TrimPrefix(hex, "#"))})
+	}
+	*s = out
+	return nil
+}
+
+// Resolve turns a colour reference into "rrggbb" or "rrggbbaa".
+//
+// A reference is a colour name (blu-3), a role (accent), or a literal #rrggbb[aa]; names and
+// roles take an optional "/aa" alpha suffix, e.g. glc-0/80.
+func (p Palette) Resolve(ref string) (string, error) {
+	ref = strings.TrimSpace(ref)
+	if strings.HasPrefix(ref, "#") {
+		hex := strings.ToLower(ref[1:])
+		if (len(hex) != 6 && len(hex) != 8) || !isHexDigits(hex) {
+			return "", fmt.Errorf("invalid colour %q", ref)
+		}
+		return hex, nil
+	}
+	name, alpha, hasAlpha := strings.Cut(ref, "/")
+	if hasAlpha && (len(alpha) != 2 || !isHexDigits(alpha)) {
+		return "", fmt.Errorf("invalid alpha in %q (want name/aa)", ref)
+	}
+	if role, ok := p.Roles[name]; ok {
+		name = role
+	}
+	i := slices.IndexFunc(p.Colors, func(s Swatch) bool { return s.Name == name })
+	if i < 0 {
+		return "", fmt.Errorf("unknown colour %q", ref)
+	}
+	return p.Colors[i].Hex + strings.ToLower(alpha), nil
+}
+
+func isHexDigits(s string) bool {
+	return strings.Trim(strings.ToLower(s), "0123456789abcdef") == ""
+}
+
+// ThemePath returns the $HOME-relative path to theme.yaml.
+func ThemePath() string {
+	return ConfigPath("theme")
+}
+
+// LoadTheme reads $HOME/dotfiles/cmds/config/theme.yaml.
+func LoadTheme() (ThemeConfig, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return ThemeConfig{}, err
+	}
+	return LoadThemeFile(filepath.Join(home, ThemePath()))
+}
+
+// LoadThemeFile reads a theme file and checks that every role names a colour in its palette.
+func LoadThemeFile(path string) (ThemeConfig, error) {
+	var cfg ThemeConfig
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return cfg, err
+	}
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return cfg, fmt.Errorf("%s: %w", path, err)
+	}
+	if _, ok := cfg.Palettes[cfg.Default]; !ok {
+		return cfg, fmt.Errorf("theme: default palette %q is not defined", cfg.Default)
+	}
+	for name, p := range cfg.Palettes {
+		for role, ref := range p.Roles {
+			if _, err := p.Resolve(ref); err != nil {
+				return cfg, fmt.Errorf("theme: palette %s: role %s: %w", name, role, err)
+			}
+		}
+	}
+	return cfg, nil
+}
+
+// Palette returns the named palette, or the active one when name is empty.
+func (c ThemeConfig) Palette(name string) (string, Palette, error) {
+	if name == "" {
+		name = c.Active()
+	}
+	p, ok := c.Palettes[name]
+	if !ok {
+		return name, Palette{}, fmt.Errorf("unknown palette %q", name)
+	}
+	return name, p, nil
+}
+
+// ActivePalette loads theme.yaml and returns the palette last applied by `dctl theme apply`.
+func ActivePalette() (string, Palette, error) {
+	cfg, err := LoadTheme()
+	if err != nil {
+		return "", Palette{}, err
+	}
+	return cfg.Palette("")
+}
+
+// activeThemeFile records the applied palette name outside the repo, so switching palettes
+// does not dirty theme.yaml: $XDG_STATE_HOME/dotfiles/theme.
+func activeThemeFile() (string, error) {
+	state := os.Getenv("XDG_STATE_HOME")
+	if state == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		state = filepath.Join(home, ".local", "state")
+	}
+	return filepath.Join(state, "dotfiles", "theme"), nil
+}
+
+// Active returns the applied palette name, falling back to the theme default when none
+// was applied or the recorded one no longer exists.
+func (c ThemeConfig) Active() string {
+	path, err := activeThemeFile()
+	if err != nil {
+		return c.Default
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			fmt.Fprintf(os.Stderr, "cmds: theme: %v\n", err)
+		}
+		return c.Default
+	}
+	name := strings.TrimSpace(string(data))
+	if _, ok := c.Palettes[name]; !ok {
+		return c.Default
+	}
+	return name
+}
+
+// SetActiveTheme records name as the applied palette.
+func SetActiveTheme(name string) error {
+	path, err := activeThemeFile()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, []byte(name+"\n"), 0o644)
+}
+
+// resolveStyleColors rewrites palette references in notify styles to #rrggbb[aa] using the
+// active palette. Literal hex values pass through; unresolvable references are logged and
+// cleared so dunst falls back to its urgency colours.
+func resolveStyleColors(c *NotifyConfig) {
+	var palette *Palette
+	for name, style := range c.Styles {
+		for _, field := range []*string{&style.Background, &style.Foreground, &style.Frame} {
+			if *field == "" || strings.HasPrefix(*field, "#") {
+				continue
+			}
+			if palette == nil {
+				_, p, err := ActivePalette()
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "hyprd: notify.styles: %v\n", err)
+					p = Palette{}
+				}
+				palette = &p
+			}
+			hex, err := palette.Resolve(*field)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "hyprd: notify.styles.%s: %v\n", name, err)
+				*field = ""
+				continue
+			}
+			*field = "#" + hex
+		}
+		c.Styles[name] = style
+	}
+}
@@ -0,0 +1,239 @@
+package daemon
+
+// update.go rebuilds a daemon binary in place, keeps the previous one, and rolls back when
+// the new binary does not come up.
+
+import (
+	"cmp"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"runtime/debug"
+	"slices"
+	"syscall"
+	"time"
+)
+
+// buildStamp is the build time Updater.Build passes with -ldflags -X. It also identifies
+// the binary, so the watchdog can tell the new daemon from the one it replaced.
+var buildStamp string
+
+const (
+	defaultUpdateDeadline = 20 * time.Second
+	updateSettle          = 5 * time.Second // a crash loop shows up as a dead socket soon after startup
+)
+
+// BuildInfo describes the running binary: VCS data stamped by the Go toolchain plus the
+// build time from Updater.Build (binary mtime for a plain `go build`).
+type BuildInfo struct {
+	Commit    string    `json:"commit,omitempty"`
+	Dirty     bool      `json:"dirty"`
+	Built     time.Time `json:"built,omitzero"`
+	GoVersion string    `json:"go"`
+	Stamp     string    `json:"stamp,omitempty"`
+}
+
+func ReadBuildInfo() BuildInfo {
+	info := BuildInfo{Stamp: buildStamp}
+	if bi, ok := debug.ReadBuildInfo(); ok {
+		info.GoVersion = bi.GoVersion
+		for _, s := range bi.Settings {
+			switch s.Key {
+			case "vcs.revision":
+				info.Commit = s.Value
+			case "vcs.modified":
+				info.Dirty = s.Value == "true"
+			}
+		}
+	}
+	if t, err := time.Parse(time.RFC3339Nano, buildStamp); err == nil {
+		info.Built = t.Local()
+	} else if exe, err := os.Executable(); err == nil {
+		if st, err := os.Stat(exe); err == nil {
+			info.Built = st.ModTime()
+		}
+	}
+	return info
+}
+
+// String renders e.g. "3f9c2a1b7d04-dirty built 2026-10-18 14:02:11 (go1.27)".
+func (b BuildInfo) String() string {
+	commit := "unknown commit"
+	if b.Commit != "" {
+		commit = b.Commit[:min(12, len(b.Commit))]
+	}
+	if b.Dirty {
+		commit += "-dirty"
+	}
+	built := "unknown time"
+	if !b.Built.IsZero() {
+		built = b.Built.Format(time.DateTime)
+	}
+	return fmt.Sprintf("%s built %s (%s)", commit, built, b.GoVersion)
+}
+
+// JSON is the payload for a daemon's `version` command.
+func (b BuildInfo) JSON() string {
+	data, err := json.Marshal(b)
+	if err != nil {
+		return fmt.Sprintf("error: %v", err)
+	}
+	return string(data)
+}
+
+// Updater rebuilds and replaces ~/.local/bin/<Name>.
+//
+// Install keeps the replaced binary as <Name>.prev and starts a watchdog from it; Watch
+// waits for the new daemon to answer `version` with the new stamp and otherwise calls
+// Rollback, which puts the previous binary and state file back and restarts Unit.
+type Updater struct {
+	Name        string   // binary name, e.g. "hyprd"
+	Package     string   // build path in the cmds module, e.g. "./cmd/hyprd"
+	Socket      string   // daemon command socket the watchdog polls
+	Unit        string   // systemd user unit restarted after a rollback
+	StateFile   string   // optional state handoff, restored with the binary
+	BuildPrefix []string // wraps `go build`, e.g. taskset CPU pinning
+	Deadline    time.Duration
+}
+
+func (u *Updater) BinPath() string {
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, ".local", "bin", u.Name)
+}
+
+// Build compiles the daemon to <bin>.new and returns its build stamp.
+func (u *Updater) Build() (string, error) {
+	bin := u.BinPath()
+	if err := os.MkdirAll(filepath.Dir(bin), 0o755); err != nil {
+		return "", fmt.Errorf("install dir: %w", err)
+	}
+	stamp := time.Now().UTC().Format(time.RFC3339Nano)
+	ldflags := fmt.Sprintf("-X %s.buildStamp=%s", reflect.TypeFor[BuildInfo]().PkgPath(), stamp)
+	args := append(slices.Clone(u.BuildPrefix), "go", "build", "-ldflags", ldflags, "-o", bin+".new", u.Package)
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.Dir = cmdsDir()
+	if out, err := cmd.CombinedOutput(); err != nil {
+		os.Remove(bin + ".new")
+		return "", fmt.Errorf("build failed: %v\n%s", err, out)
+	}
+	return stamp, nil
+}
+
+// Install swaps <bin>.new in, keeping the current binary (and state file, if written) as
+// .prev, and starts the watchdog that verifies stamp once the caller restarts the daemon.
+func (u *Updater) Install(stamp string) error {
+	bin := u.BinPath()
+	if u.StateFile != "" {
+		if data, err := os.ReadFile(u.StateFile); err == nil {
+			if err := os.WriteFile(u.StateFile+".prev", data, 0o600); err != nil {
+				os.Remove(bin + ".new")
+				return fmt.Errorf("keep state: %w", err)
+			}
+		}
+	}
+	hasPrev := false
+	if _, err := os.Stat(bin); err == nil {
+		if err := os.Rename(bin, bin+".prev"); err != nil {
+			os.Remove(bin + ".new")
+			return fmt.Errorf("keep previous binary: %w", err)
+		}
+		hasPrev = true
+	}
+	if err := os.Rename(bin+".new", bin); err != nil {
+		if hasPrev {
+			os.Rename(bin+".prev", bin)
+		}
+		os.Remove(bin + ".new")
+		return fmt.Errorf("install: %w", err)
+	}
+	if !hasPrev {
+		return nil
+	}
+	return u.startWatchdog(stamp)
+}
+
+// startWatchdog runs `<bin>.prev rebuild --watchdog <stamp>` outside the daemon's cgroup
+// (systemd-run), so a crashing unit restart does not take the watchdog with it.
+func (u *Updater) startWatchdog(stamp string) error {
+	args := []string{u.BinPath() + ".prev", "rebuild", "--watchdog", stamp}
+	if err := exec.Command("systemd-run", append([]string{"--user", "--collect", "--quiet"}, args...)...).Run(); err == nil {
+		return nil
+	}
+	cmd := exec.Command(args[0], args[1:]...)
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setsid: true}
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("start watchdog: %w", err)
+	}
+	return cmd.Process.Release()
+}
+
+// Watch waits for the daemon to answer `version` with stamp and stay up through a short
+// settle period, rolling back otherwise.
+func (u *Updater) Watch(stamp string) error {
+	client := NewClient(u.Socket)
+	wait := cmp.Or(u.Deadline, defaultUpdateDeadline)
+	deadline := time.Now().Add(wait)
+	for !u.answers(client, stamp) {
+		if time.Now().After(deadline) {
+			return u.Rollback(fmt.Sprintf("new %s did not answer within %s", u.Name, wait))
+		}
+		time.Sleep(250 * time.Millisecond)
+	}
+	time.Sleep(updateSettle)
+	if !u.answers(client, stamp) {
+		return u.Rollback(fmt.Sprintf("new %s stopped answering after startup", u.Name))
+	}
+	if u.StateFile != "" {
+		os.Remove(u.StateFile + ".prev")
+	}
+	fmt.Fprintf(os.Stderr, "%s: rebuild %s verified\n", u.Name, stamp)
+	return nil
+}
+
+func (u *Updater) answers(client *Client, stamp string) bool {
+	resp, err := client.Send("version")
+	if err != nil {
+		return false
+	}
+	var info BuildInfo
+	return json.Unmarshal([]byte(resp), &info) == nil && info.Stamp == stamp
+}
+
+// Rollback restores <bin>.prev and the saved state file, keeps the rejected binary as
+// <bin>.failed, and restarts the unit.
+func (u *Updater) Rollback(reason string) error {
+	bin := u.BinPath()
+	fmt.Fprintf(os.Stderr, "%s: rolling back: %s\n", u.Name, reason)
+	if _, err := os.Stat(bin + ".prev"); err != nil {
+		return fmt.Errorf("no previous binary to restore: %w", err)
+	}
+	os.Rename(bin, bin+".failed")
+	if err := os.Rename(bin+".prev", bin); err != nil {
+		return fmt.Errorf("restore previous binary: %w", err)
+	}
+	if u.StateFile != "" {
+		if data, err := os.ReadFile(u.StateFile + ".prev"); err == nil {
+			if err := os.WriteFile(u.StateFile, data, 0o600); err != nil {
+				fmt.Fprintf(os.Stderr, "%s: restore state: %v\n", u.Name, err)
+			}
+			os.Remove(u.StateFile + ".prev")
+		}
+	}
+	exec.Command("systemctl", "--user", "reset-failed", u.Unit).Run()
+	if out, err := exec.Command("systemctl", "--user", "restart", u.Unit).CombinedOutput(); err != nil {
+		return fmt.Errorf("restart %s: %v: %s", u.Unit, err, out)
+	}
+	return fmt.Errorf("rolled back to previous %s: %s", u.Name, reason)
+}
+
+// cmdsDir is the dotfiles Go module: $DOTFILES/cmds, else ~/dotfiles/cmds.
+func cmdsDir() string {
+	if dotfiles := os.Getenv("DOTFILES"); dotfiles != "" {
+		return filepath.Join(dotfiles, "cmds")
+	}
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, "dotfiles", "cmds")
+}
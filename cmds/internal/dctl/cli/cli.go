@@ -13,6 +13,7 @@ import (
 	"dotfiles/cmds/internal/dctl/iso"
 	"dotfiles/cmds/internal/dctl/repos"
 	"dotfiles/cmds/internal/dctl/secrets"
+	"dotfiles/cmds/internal/dctl/theme"
 	"dotfiles/cmds/internal/dctl/update"
 )
 
@@ -29,6 +30,7 @@ type CLI struct {
 	Install install.Cmd `cmd:"" group:"lifecycle" help:"Run dotfiles install steps."`
 	Repos   repos.Cmd   `cmd:"" group:"lifecycle" help:"Manage configured repositories."`
 	ISO     iso.Cmd     `cmd:"" name:"iso" group:"lifecycle" help:"Build and release custom Arch ISOs."`
+	Theme   theme.Cmd   `cmd:"" group:"lifecycle" help:"Render and apply colour palettes."`
 }
 
 func NormalizeArgs(args []string) []string {
@@ -0,0 +1,267 @@
+// Package theme renders the shared colour palette into desktop app config.
+//
+// Responsibilities:
+// - Render cmds/config/theme/*.tmpl from a palette in cmds/config/theme.yaml.
+// - Write only outputs whose content changed and record the applied palette.
+// - Live-reload kitty, dunst, eww, Hyprland, and the hyprd accent.
+package theme
+
+// theme.go defines the theme CLI, render targets, and per-app reload hooks.
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"text/template"
+
+	"dotfiles/cmds/internal/config"
+	"dotfiles/cmds/internal/dctl/app"
+	"dotfiles/cmds/internal/dctl/execx"
+	"dotfiles/cmds/internal/dctl/output"
+	"dotfiles/cmds/internal/dctl/paths"
+)
+
+type Cmd struct {
+	Apply ApplyCmd `cmd:"" help:"Render app configs from a palette and reload running apps."`
+	List  ListCmd  `cmd:"" help:"List palettes."`
+}
+type ApplyCmd struct {
+	Name     string `arg:"" optional:"" help:"Palette name; defaults to the active palette."`
+	DryRun   bool   `short:"n" help:"Show which files would change without writing or reloading."`
+	NoReload bool   `help:"Write files without reloading running apps."`
+}
+type ListCmd struct{}
+
+type Options struct {
+	DryRun   bool
+	NoReload bool
+}
+
+// target is one rendered file: Template under cmds/config/theme, Output under config/.
+type target struct {
+	Name     string
+	Template string
+	Output   []string
+	Reload   func(ctx context.Context, runner execx.Runner, output string) error
+}
+
+var targets = []target{
+	{Name: "kitty", Template: "kitty.conf.tmpl", Output: []string{"kitty", "theme.conf"}, Reload: reloadKitty},
+	{Name: "dunst", Template: "dunst.conf.tmpl", Output: []string{"dunst", "dunstrc.d", "50-theme.conf"}, Reload: reloadDunst},
+	{Name: "eww", Template: "eww.scss.tmpl", Output: []string{"eww", "styles", "_palette.scss"}, Reload: reloadEww},
+	{Name: "hypr", Template: "hypr.lua.tmpl", Output: []string{"hypr", "theme.lua"}, Reload: reloadHypr},
+}
+
+// Result reports one target for --json output.
+type Result struct {
+	Target  string `json:"target"`
+	Output  string `json:"output"`
+	Changed bool   `json:"changed"`
+	Reload  string `json:"reload,omitempty"` // "ok", "skipped", or the reload error
+}
+
+func themeFile(root paths.Root) string {
+	return filepath.Join(root.Dotfiles, "cmds", "config", "theme.yaml")
+}
+
+func templateDir(root paths.Root) string {
+	return filepath.Join(root.Dotfiles, "cmds", "config", "theme")
+}
+
+func (c *ListCmd) Run(ctx *app.Context) error {
+	cfg, err := config.LoadThemeFile(themeFile(ctx.Root))
+	if err != nil {
+		return err
+	}
+	active := cfg.Active()
+	names := slices.Sorted(maps.Keys(cfg.Palettes))
+	if ctx.Output.JSONMode() {
+		return ctx.Output.Emit(map[string]any{"active": active, "palettes": names})
+	}
+	for _, name := range names {
+		marker := "  "
+		if name == active {
+			marker = "* "
+		}
+		fmt.Fprintf(ctx.Output.Writer(), "%s%-16s %d colours\n", marker, name, len(cfg.Palettes[name].Colors))
+	}
+	return nil
+}
+
+func (c *ApplyCmd) Run(ctx *app.Context) error {
+	return Apply(ctx.Context, ctx.Root, ctx.Output, c.Name, Options{DryRun: c.DryRun, NoReload: c.NoReload}, nil)
+}
+
+// Apply renders every target for palette name ("" keeps the active palette), writes the
+// outputs that changed, records the palette, and reloads the apps whose output changed.
+func Apply(ctx context.Context, root paths.Root, out *output.Printer, name string, opts Options, runner execx.Runner) error {
+	cfg, err := config.LoadThemeFile(themeFile(root))
+	if err != nil {
+		return err
+	}
+	name, palette, err := cfg.Palette(name)
+	if err != nil {
+		return err
+	}
+	if runner == nil {
+		runner = execx.OSRunner{}
+	}
+
+	out.Header("Applying theme %s", name)
+	results := make([]Result, 0, len(targets))
+	changed := 0
+	for _, t := range targets {
+		output := root.Config(t.Output...)
+		res := Result{Target: t.Name, Output: output}
+		data, err := render(filepath.Join(templateDir(root), t.Template), name, palette)
+		if err != nil {
+			return fmt.Errorf("%s: %w", t.Name, err)
+		}
+		if current, err := os.ReadFile(output); err == nil && bytes.Equal(current, data) {
+			out.Step("%s - unchanged", t.Name)
+			results = append(results, res)
+			continue
+		}
+		res.Changed = true
+		changed++
+		if opts.DryRun {
+			out.Step("%s - would write %s", t.Name, output)
+			results = append(results, res)
+			continue
+		}
+		if err := writeFileAtomic(output, data, 0o644); err != nil {
+			return fmt.Errorf("%s: %w", t.Name, err)
+		}
+		out.Step("%s - wrote %s", t.Name, output)
+		results = append(results, res)
+	}
+	if opts.DryRun {
+		out.OK("Dry run: %d of %d files would change", changed, len(targets))
+		return emit(out, name, results)
+	}
+	if err := config.SetActiveTheme(name); err != nil {
+		return fmt.Errorf("record active theme: %w", err)
+	}
+
+	// hyprd repaints after Hyprland reloads, so the accent lands on top of the fresh config.
+	// It reloads even when no file changed: its colours come straight from theme.yaml.
+	for i, t := range targets {
+		switch {
+		case opts.NoReload:
+			results[i].Reload = "skipped"
+		case !results[i].Changed:
+			continue
+		default:
+			results[i].Reload = "ok"
+			if err := t.Reload(ctx, runner, results[i].Output); err != nil {
+				results[i].Reload = err.Error()
+				out.Warn("%s reload: %v", t.Name, err)
+			}
+		}
+	}
+	if !opts.NoReload {
+		if _, err := runner.Run(ctx, "", "hyprd", "theme", "reload"); err != nil {
+			out.Warn("hyprd reload: %v", err)
+		}
+	}
+	out.OK("Theme %s applied (%d files changed)", name, changed)
+	return emit(out, name, results)
+}
+
+func emit(out *output.Printer, name string, results []Result) error {
+	if !out.JSONMode() {
+		return nil
+	}
+	return out.Emit(map[string]any{"palette": name, "targets": results})
+}
+
+// render executes one template with the palette's ordered colours and two lookups:
+// hex "ref" yields "#rrggbb[aa]" and raw "ref" yields "rrggbb[aa]" (for Hyprland rgba()).
+func render(path, name string, palette config.Palette) ([]byte, error) {
+	funcs := template.FuncMap{
+		"hex": func(ref string) (string, error) {
+			c, err := palette.Resolve(ref)
+			return "#" + c, err
+		},
+		"raw": palette.Resolve,
+	}
+	tmpl, err := template.New(filepath.Base(path)).Funcs(funcs).Option("missingkey=error").ParseFiles(path)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	data := struct {
+		Name   string
+		Colors config.Swatches
+	}{name, palette.Colors}
+	if err := tmpl.Execute(&buf, data); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// reloadKitty pushes the new colours to every running kitty over its remote-control socket.
+// Sockets left behind by exited instances are skipped.
+func reloadKitty(ctx context.Context, runner execx.Runner, output string) error {
+	sockets, err := filepath.Glob("/tmp/kitty-*")
+	if err != nil {
+		return err
+	}
+	var failed []string
+	for _, socket := range sockets {
+		if st, err := os.Stat(socket); err != nil || st.Mode()&os.ModeSocket == 0 {
+			continue
+		}
+		if _, err := runner.Run(ctx, "", "kitty", "@", "--to", "unix:"+socket, "set-colors", "--all", "--configured", output); err != nil {
+			failed = append(failed, filepath.Base(socket))
+		}
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("set-colors failed for %s", strings.Join(failed, ", "))
+	}
+	return nil
+}
+
+func reloadDunst(ctx context.Context, runner execx.Runner, _ string) error {
+	_, err := runner.Run(ctx, "", "dunstctl", "reload")
+	return err
+}
+
+func reloadEww(ctx context.Context, runner execx.Runner, _ string) error {
+	_, err := runner.Run(ctx, "", "eww", "reload")
+	return err
+}
+
+func reloadHypr(ctx context.Context, runner execx.Runner, _ string) error {
+	_, err := runner.Run(ctx, "", "hyprctl", "reload")
+	return err
+}
+
+func writeFileAtomic(path string, data []byte, mode os.FileMode) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".tmp.*")
+	if err != nil {
+		return err
+	}
+	tmpName := tmp.Name()
+	defer os.Remove(tmpName)
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Chmod(mode); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmpName, path)
+}
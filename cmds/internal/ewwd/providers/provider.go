@@ -8,7 +8,10 @@ package providers
 
 // provider.go defines shared provider interfaces used by ewwd runtime wiring.
 
-import "context"
+import (
+	"context"
+	"encoding/json"
+)
 
 // Provider monitors a subsystem and pushes state snapshots via notify.
 //
@@ -24,3 +27,11 @@ type ActionProvider interface {
 	Provider
 	HandleAction(args []string) (string, error)
 }
+
+// StatefulProvider extends Provider with user-set state (a running countdown) that
+// `ewwd rebuild` carries over to the new binary. RestoreState runs before Start.
+type StatefulProvider interface {
+	Provider
+	SaveState() (json.RawMessage, error)
+	RestoreState(data json.RawMessage) error
+}
@@ -5,6 +5,7 @@ package providers
 import (
 	"context"
 	"dotfiles/cmds/internal/config"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"os"
@@ -138,6 +139,52 @@ func (t *Timer) getState() *TimerState {
 	return t.getStateLocked()
 }
 
+// timerSnapshot is the Timer state carried across `ewwd rebuild`.
+type timerSnapshot struct {
+	TimerHours      int  `json:"timer_hours"`
+	TimerMinutes    int  `json:"timer_minutes"`
+	TimerRunning    bool `json:"timer_running"`
+	AlarmTargetHour int  `json:"alarm_target_hour"`
+	AlarmTargetMin  int  `json:"alarm_target_min"`
+	AlarmRunning    bool `json:"alarm_running"`
+}
+
+func (t *Timer) SaveState() (json.RawMessage, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return json.Marshal(timerSnapshot{
+		TimerHours:      t.timerHours,
+		TimerMinutes:    t.timerMinutes,
+		TimerRunning:    t.timerRunning,
+		AlarmTargetHour: t.alarmTargetHour,
+		AlarmTargetMin:  t.alarmTargetMin,
+		AlarmRunning:    t.alarmRunning,
+	})
+}
+
+// RestoreState takes back a snapshot from SaveState and resumes any running countdown.
+func (t *Timer) RestoreState(data json.RawMessage) error {
+	var snap timerSnapshot
+	if err := json.Unmarshal(data, &snap); err != nil {
+		return err
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.timerHours, t.timerMinutes = snap.TimerHours, snap.TimerMinutes
+	t.alarmTargetHour, t.alarmTargetMin = snap.AlarmTargetHour, snap.AlarmTargetMin
+	if snap.TimerRunning && !t.timerRunning {
+		t.timerRunning = true
+		t.timerStop = make(chan struct{})
+		go t.timerCountdownLoop()
+	}
+	if snap.AlarmRunning && !t.alarmRunning && t.alarmRemaining() > 0 {
+		t.alarmRunning = true
+		t.alarmStop = make(chan struct{})
+		go t.alarmCountdownLoop()
+	}
+	return nil
+}
+
 // alarmRemaining returns minutes until alarmTarget, rolling to tomorrow if past; caller must hold t.mu.
 func (t *Timer) alarmRemaining() int {
 	now := time.Now()
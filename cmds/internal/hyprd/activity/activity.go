@@ -0,0 +1,230 @@
+// Package activity records window focus intervals to a local SQLite database and reports on them.
+//
+// Responsibilities:
+// - Open one interval per focused window, workspace, session, and project.
+// - Pause recording while the desktop is away or locked.
+// - Summarize recorded time for `hyprd stats`.
+package activity
+
+// activity.go owns the database schema and the Recorder fed by the event loop.
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"dotfiles/cmds/internal/config"
+
+	_ "modernc.org/sqlite"
+)
+
+const schema = `
+CREATE TABLE IF NOT EXISTS focus (
+	id        INTEGER PRIMARY KEY,
+	started   INTEGER NOT NULL, -- unix seconds
+	ended     INTEGER NOT NULL, -- advanced by the heartbeat while the interval is open
+	class     TEXT NOT NULL,
+	title     TEXT NOT NULL,
+	workspace INTEGER NOT NULL,
+	session   TEXT NOT NULL,
+	project   TEXT NOT NULL
+);
+CREATE INDEX IF NOT EXISTS focus_started ON focus (started);
+`
+
+const (
+	// heartbeat bounds how much of an open interval a crash loses and how stale `stats` can be.
+	heartbeat = 30 * time.Second
+	// minInterval drops focus passing through a window, e.g. while cycling workspaces.
+	minInterval = 2 * time.Second
+)
+
+// Path returns the database file for cfg: activity.database, or
+// $XDG_STATE_HOME/hyprd/activity.db (default ~/.local/state).
+func Path(cfg config.ActivityConfig) (string, error) {
+	if cfg.Database != "" {
+		return config.ExpandPath(cfg.Database), nil
+	}
+	dir := os.Getenv("XDG_STATE_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		dir = filepath.Join(home, ".local", "state")
+	}
+	return filepath.Join(dir, "hyprd", "activity.db"), nil
+}
+
+// Open opens or creates the database at path in WAL mode, so `hyprd stats` can read while
+// the daemon writes.
+func Open(path string) (*sql.DB, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, err
+	}
+	db, err := sql.Open("sqlite", path+"?_pragma=journal_mode(WAL)&_pragma=synchronous(NORMAL)&_pragma=busy_timeout(5000)")
+	if err != nil {
+		return nil, err
+	}
+	if _, err := db.Exec(schema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return db, nil
+}
+
+// Focus is what the user is looking at: the active window and where it lives.
+type Focus struct {
+	Class     string
+	Title     string
+	Workspace int
+	Session   string
+	Project   string
+}
+
+// Recorder writes one row per focus interval while enabled and present.
+//
+// The open interval's row is inserted when it starts and its end is advanced on every
+// change and heartbeat, so the database never lags the desktop by more than a heartbeat.
+type Recorder struct {
+	mu      sync.Mutex
+	cfg     config.ActivityConfig
+	db      *sql.DB
+	path    string
+	present bool
+	focus   Focus     // last observed focus, kept while paused so resuming reopens it
+	openID  int64     // row of the open interval; 0 when none is open
+	openAt  time.Time // start of the open interval
+}
+
+func NewRecorder() *Recorder {
+	return &Recorder{present: true}
+}
+
+// Configure applies activity config, opening the database when recording is enabled and
+// closing it when disabled or moved.
+func (r *Recorder) Configure(cfg config.ActivityConfig) error {
+	path, err := Path(cfg)
+	if err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	now := time.Now()
+	r.cfg = cfg
+	if r.db != nil && (!cfg.Enabled || path != r.path) {
+		r.closeLocked(now)
+		r.db.Close()
+		r.db = nil
+	}
+	if cfg.Enabled && r.db == nil {
+		db, err := Open(path)
+		if err != nil {
+			return err
+		}
+		r.db, r.path = db, path
+		r.openLocked(now)
+	}
+	return nil
+}
+
+// Observe records a focus change; repeated focus is ignored.
+func (r *Recorder) Observe(f Focus) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if f == r.focus {
+		return
+	}
+	now := time.Now()
+	r.closeLocked(now)
+	r.focus = f
+	r.openLocked(now)
+}
+
+// SetPresent pauses recording while away or locked and resumes it on return.
+func (r *Recorder) SetPresent(present bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if present == r.present {
+		return
+	}
+	r.present = present
+	if present {
+		r.openLocked(time.Now())
+	} else {
+		r.closeLocked(time.Now())
+	}
+}
+
+// Run advances the open interval every heartbeat until done closes.
+func (r *Recorder) Run(done <-chan struct{}) {
+	ticker := time.NewTicker(heartbeat)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+			r.mu.Lock()
+			if r.openID != 0 {
+				r.exec("UPDATE focus SET ended = ? WHERE id = ?", time.Now().Unix(), r.openID)
+			}
+			r.mu.Unlock()
+		}
+	}
+}
+
+// Close ends the open interval and closes the database.
+func (r *Recorder) Close() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.db == nil {
+		return
+	}
+	r.closeLocked(time.Now())
+	r.db.Close()
+	r.db = nil
+}
+
+// openLocked starts an interval for the current focus when recording applies to it.
+func (r *Recorder) openLocked(now time.Time) {
+	f := r.focus
+	if r.db == nil || !r.present || r.openID != 0 || f.Class == "" || r.cfg.Ignores(f.Class) {
+		return
+	}
+	res, err := r.db.Exec(
+		"INSERT INTO focus (started, ended, class, title, workspace, session, project) VALUES (?, ?, ?, ?, ?, ?, ?)",
+		now.Unix(), now.Unix(), f.Class, f.Title, f.Workspace, f.Session, f.Project,
+	)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "hyprd activity: %v\n", err)
+		return
+	}
+	r.openID, _ = res.LastInsertId()
+	r.openAt = now
+}
+
+// closeLocked ends the open interval, dropping it when shorter than minInterval.
+func (r *Recorder) closeLocked(now time.Time) {
+	if r.openID == 0 {
+		return
+	}
+	if now.Sub(r.openAt) < minInterval {
+		r.exec("DELETE FROM focus WHERE id = ?", r.openID)
+	} else {
+		r.exec("UPDATE focus SET ended = ? WHERE id = ?", now.Unix(), r.openID)
+	}
+	r.openID = 0
+}
+
+func (r *Recorder) exec(query string, args ...any) {
+	if r.db == nil {
+		return
+	}
+	if _, err := r.db.Exec(query, args...); err != nil {
+		fmt.Fprintf(os.Stderr, "hyprd activity: %v\n", err)
+	}
+}
@@ -0,0 +1,151 @@
+package activity
+
+// report.go reads recorded intervals back and totals them for `hyprd stats`.
+
+import (
+	"cmp"
+	"database/sql"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+)
+
+// Groupings accepted by `stats --by`.
+var Groupings = []string{"project", "class", "session"}
+
+// Interval is one recorded focus period, clipped to the queried range.
+type Interval struct {
+	Start     time.Time `json:"start"`
+	End       time.Time `json:"end"`
+	Class     string    `json:"class"`
+	Title     string    `json:"title"`
+	Workspace int       `json:"workspace"`
+	Session   string    `json:"session,omitempty"`
+	Project   string    `json:"project,omitempty"`
+}
+
+// Load returns intervals overlapping [since, until) in start order, clipped to the range.
+func Load(db *sql.DB, since, until time.Time) ([]Interval, error) {
+	rows, err := db.Query(
+		"SELECT started, ended, class, title, workspace, session, project FROM focus WHERE ended > ? AND started < ? ORDER BY started",
+		since.Unix(), until.Unix(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []Interval
+	for rows.Next() {
+		var started, ended int64
+		var iv Interval
+		if err := rows.Scan(&started, &ended, &iv.Class, &iv.Title, &iv.Workspace, &iv.Session, &iv.Project); err != nil {
+			return nil, err
+		}
+		iv.Start = time.Unix(max(started, since.Unix()), 0)
+		iv.End = time.Unix(min(ended, until.Unix()), 0)
+		out = append(out, iv)
+	}
+	return out, rows.Err()
+}
+
+// Range resolves a report period: "today" from local midnight, "week" over the last seven
+// days including today.
+func Range(period string, now time.Time) (time.Time, error) {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	switch period {
+	case "", "today":
+		return today, nil
+	case "week":
+		return today.AddDate(0, 0, -6), nil
+	default:
+		return time.Time{}, fmt.Errorf("unknown period %q (want today|week)", period)
+	}
+}
+
+// Total is focused time for one group.
+type Total struct {
+	Key     string `json:"key"`
+	Seconds int64  `json:"seconds"`
+}
+
+// Report totals focused time per group over a period.
+type Report struct {
+	Since   time.Time `json:"since"`
+	Until   time.Time `json:"until"`
+	By      string    `json:"by"`
+	Seconds int64     `json:"seconds"`
+	Totals  []Total   `json:"totals"` // longest first
+}
+
+// Summarize groups intervals by project, class, or session. Intervals without a project or
+// session are grouped under "(none)".
+func Summarize(intervals []Interval, by string, since, until time.Time) Report {
+	report := Report{Since: since, Until: until, By: by, Totals: []Total{}}
+	seconds := make(map[string]int64)
+	for _, iv := range intervals {
+		key := iv.Class
+		switch by {
+		case "project":
+			key = displayPath(iv.Project)
+		case "session":
+			key = iv.Session
+		}
+		if key == "" {
+			key = "(none)"
+		}
+		d := int64(iv.End.Sub(iv.Start).Seconds())
+		seconds[key] += d
+		report.Seconds += d
+	}
+	for key, s := range seconds {
+		report.Totals = append(report.Totals, Total{Key: key, Seconds: s})
+	}
+	slices.SortFunc(report.Totals, func(a, b Total) int {
+		return cmp.Or(cmp.Compare(b.Seconds, a.Seconds), cmp.Compare(a.Key, b.Key))
+	})
+	return report
+}
+
+// String renders the report as aligned rows with hours, minutes, and share of the total.
+func (r Report) String() string {
+	lines := []string{fmt.Sprintf("focus by %s since %s", r.By, r.Since.Format("2006-01-02 15:04"))}
+	if len(r.Totals) == 0 {
+		return lines[0] + "\nnothing recorded"
+	}
+	width := len("total")
+	for _, t := range r.Totals {
+		width = max(width, len(t.Key))
+	}
+	row := func(key string, seconds int64) string {
+		share := 0.0
+		if r.Seconds > 0 {
+			share = 100 * float64(seconds) / float64(r.Seconds)
+		}
+		return fmt.Sprintf("%-*s  %7s  %5.1f%%", width, key, formatDuration(seconds), share)
+	}
+	for _, t := range r.Totals {
+		lines = append(lines, row(t.Key, t.Seconds))
+	}
+	lines = append(lines, row("total", r.Seconds))
+	return strings.Join(lines, "\n")
+}
+
+func formatDuration(seconds int64) string {
+	d := (time.Duration(seconds) * time.Second).Round(time.Minute)
+	return fmt.Sprintf("%dh%02dm", int(d.Hours()), int(d.Minutes())%60)
+}
+
+// displayPath shortens $HOME to ~ in project paths.
+func displayPath(path string) string {
+	home, err := os.UserHomeDir()
+	if err != nil || home == "" {
+		return path
+	}
+	if rest, ok := strings.CutPrefix(path, home+"/"); ok {
+		return "~/" + rest
+	}
+	return path
+}
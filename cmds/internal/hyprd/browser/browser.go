@@ -198,6 +198,16 @@ func (b *Browser) RestoreConfiguredSnapshots(configs []config.BrowserConfig, dry
 	return b.injectAndLaunch(payload, profile, true, dryRun)
 }
 
+// LaunchURLs opens urls as tabs of a new Firefox window on workspace, for URL-list sessions.
+func (b *Browser) LaunchURLs(urls []string, workspace int) error {
+	if len(urls) == 0 {
+		return fmt.Errorf("no browser urls to open")
+	}
+	cmd := append(b.browserCommandParts(), "--new-window")
+	cmd = append(cmd, urls...)
+	return b.hypr.ExecOnWorkspace(shellQuoteCommand(cmd), workspace, true)
+}
+
 // ClaimWindowForSnapshot finds the restored Firefox window for snapshot and moves it to workspace.
 func (b *Browser) ClaimWindowForSnapshot(snapshot string, workspace int) error {
 	return b.ClaimWindow(snapshot, workspace)
@@ -305,6 +315,36 @@ func (b *Browser) executeSnapshot(args []string) (string, error) {
 	return b.writeSnapshot(name, profile, windowIndex, workspace, store)
 }
 
+// SnapshotWindow writes a snapshot of the session-store window shown by win and returns its directory.
+//
+// The store window is matched by selected-tab title; a stale title falls back to the largest window.
+func (b *Browser) SnapshotWindow(name string, win hypr.Window) (string, error) {
+	profile, err := discoverFirefoxProfile("")
+	if err != nil {
+		return "", err
+	}
+	store, err := b.loadFirefoxSession(profile)
+	if err != nil {
+		return "", err
+	}
+	if len(store.Windows) == 0 {
+		return "", fmt.Errorf("session file has no windows")
+	}
+
+	title := trimFirefoxTitle(win.Title)
+	windowIndex := -1
+	for i, window := range store.Windows {
+		if titlesMatch(title, selectedTabTitle(window)) {
+			windowIndex = i
+			break
+		}
+	}
+	if windowIndex < 0 {
+		windowIndex = pickBestWindow(store.Windows, allWindowIndexes(store.Windows))
+	}
+	return b.writeSnapshot(name, profile, windowIndex, win.Workspace.ID, store)
+}
+
 func (b *Browser) snapshotProfile(string) (firefoxProfile, error) {
 	return discoverFirefoxProfile("")
 }
@@ -194,6 +194,22 @@ func SnapshotSelectedTitle(name string) (string, error) {
 	return summary.Window.SelectedTitle, nil
 }
 
+// SnapshotTabCount returns the number of tabs a named snapshot restores.
+func SnapshotTabCount(name string) (int, error) {
+	dir, err := resolveSnapshotDir(name)
+	if err != nil {
+		return 0, err
+	}
+	summary, err := readSnapshotSummary(dir)
+	if err != nil {
+		return 0, err
+	}
+	if summary.Window.TabCount > 0 {
+		return summary.Window.TabCount, nil
+	}
+	return len(summary.Tabs), nil
+}
+
 // LayoutWindowTitles returns current and snapshot titles in live-match priority order.
 func (b *Browser) LayoutWindowTitles(name string) ([]string, error) {
 	dir, err := resolveSnapshotDir(name)
@@ -408,6 +424,12 @@ func loadSnapshotPayload(name string) ([]byte, error) {
 	return buildSessionPayload(dir)
 }
 
+// SnapshotExists reports whether name resolves to a snapshot.yaml under any snapshot root.
+func SnapshotExists(name string) bool {
+	_, err := resolveSnapshotDir(name)
+	return err == nil
+}
+
 func resolveSnapshotDir(name string) (string, error) {
 	slug, err := slugifySnapshotName(name)
 	if err != nil {
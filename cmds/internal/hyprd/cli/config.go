@@ -0,0 +1,55 @@
+package cli
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"dotfiles/cmds/internal/config"
+	"dotfiles/cmds/internal/hyprd/browser"
+)
+
+// Config dispatches offline config commands; `check [path]` validates hyprd.yaml without Hyprland.
+func Config() {
+	if len(os.Args) < 3 || os.Args[2] != "check" {
+		fmt.Fprintln(os.Stderr, "usage: hyprd config check [path]")
+		os.Exit(1)
+	}
+
+	path := ""
+	if len(os.Args) > 3 {
+		path = os.Args[3]
+	} else {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "hyprd config: %v\n", err)
+			os.Exit(1)
+		}
+		path = filepath.Join(home, config.ConfigPath("hyprd"))
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "hyprd config: %v\n", err)
+		os.Exit(1)
+	}
+
+	opts := config.HyprCheckOptions{SnapshotExists: browser.SnapshotExists}
+	if _, palette, err := config.ActivePalette(); err == nil {
+		opts.Palette = &palette
+	} else {
+		fmt.Fprintf(os.Stderr, "hyprd config: theme: %v (palette colour references not checked)\n", err)
+	}
+	issues := config.CheckHypr(data, opts)
+	errorCount := 0
+	for _, issue := range issues {
+		if issue.Severity == "error" {
+			errorCount++
+		}
+		fmt.Printf("%s:%s\n", path, issue)
+	}
+	fmt.Printf("%d errors, %d warnings\n", errorCount, len(issues)-errorCount)
+	if errorCount > 0 {
+		os.Exit(1)
+	}
+}
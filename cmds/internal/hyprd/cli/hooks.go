@@ -0,0 +1,89 @@
+package cli
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"dotfiles/cmds/internal/config"
+	"dotfiles/cmds/internal/hyprd/hooks"
+)
+
+const hooksUsage = "usage: hyprd hooks test <event> [--class C] [--title T] [--workspace N] [--session S] [--project DIR]"
+
+// Hooks runs the hooks configured for an event in the foreground with a made-up payload, so
+// scripts can be tried without waiting for the real event.
+func Hooks() {
+	if err := hooksTest(os.Args[2:]); err != nil {
+		fmt.Fprintf(os.Stderr, "hyprd hooks: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func hooksTest(args []string) error {
+	if len(args) < 2 || args[0] != "test" {
+		return errors.New(hooksUsage)
+	}
+	ev := hooks.Event{Name: args[1], Time: time.Now()}
+	if !slices.Contains(config.HookEvents, ev.Name) {
+		return fmt.Errorf("unknown event %q (want %s)", ev.Name, strings.Join(config.HookEvents, "|"))
+	}
+	flags := args[2:]
+	for i := 0; i < len(flags); i += 2 {
+		if i+1 >= len(flags) {
+			return errors.New(hooksUsage)
+		}
+		value := flags[i+1]
+		switch flags[i] {
+		case "--class":
+			ev.Class = value
+		case "--title":
+			ev.Title = value
+		case "--session":
+			ev.Session = value
+		case "--project":
+			ev.Project = config.ExpandPath(value)
+		case "--workspace", "-w":
+			n, err := strconv.Atoi(value)
+			if err != nil {
+				return fmt.Errorf("invalid workspace: %s", value)
+			}
+			ev.Workspace = n
+		default:
+			return errors.New(hooksUsage)
+		}
+	}
+
+	cfg := config.LoadHypr().Hooks
+	matching := cfg.Matching(ev.Name, ev.Class, ev.Workspace)
+	if len(matching) == 0 {
+		fmt.Printf("no hooks match %s (%d configured)\n", ev.Name, len(cfg.Events[ev.Name]))
+		return nil
+	}
+	failed := 0
+	for _, hook := range matching {
+		fmt.Printf("── %s\n", hook.Run)
+		start := time.Now()
+		out, err := hooks.Run(hook.Run, ev.Env(), ev, hooks.Timeout(hook, cfg))
+		if len(out) > 0 {
+			fmt.Print(string(out))
+			if out[len(out)-1] != '\n' {
+				fmt.Println()
+			}
+		}
+		if err != nil {
+			failed++
+			fmt.Printf("   failed: %v\n", err)
+			continue
+		}
+		fmt.Printf("   ok in %s\n", time.Since(start).Round(time.Millisecond))
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d hooks failed", failed, len(matching))
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+package cli
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"dotfiles/cmds/internal/config"
+	"dotfiles/cmds/internal/hyprd/activity"
+)
+
+const statsUsage = "usage: hyprd stats [today|week] [--by project|class|session] [--json]\n       hyprd stats export [today|week]"
+
+// Stats reads the activity database directly, so reports work whether or not the daemon runs.
+func Stats() {
+	if err := stats(os.Args[2:]); err != nil {
+		fmt.Fprintf(os.Stderr, "hyprd stats: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func stats(args []string) error {
+	export := len(args) > 0 && args[0] == "export"
+	if export {
+		args = args[1:]
+	}
+	period, by, asJSON := "", "project", false
+	for i := 0; i < len(args); i++ {
+		switch {
+		case args[i] == "--json" && !export:
+			asJSON = true
+		case args[i] == "--by" && !export && i+1 < len(args):
+			by = args[i+1]
+			i++
+		case period == "" && !strings.HasPrefix(args[i], "-"):
+			period = args[i]
+		default:
+			return errors.New(statsUsage)
+		}
+	}
+	if !slices.Contains(activity.Groupings, by) {
+		return fmt.Errorf("unknown --by %q (want %s)", by, strings.Join(activity.Groupings, "|"))
+	}
+	now := time.Now()
+	since, err := activity.Range(period, now)
+	if err != nil {
+		return err
+	}
+
+	cfg := config.LoadHypr()
+	path, err := activity.Path(cfg.Activity)
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("no activity recorded at %s (set activity.enabled in hyprd.yaml)", path)
+	}
+	db, err := activity.Open(path)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	intervals, err := activity.Load(db, since, now)
+	if err != nil {
+		return err
+	}
+
+	if export {
+		if intervals == nil {
+			intervals = []activity.Interval{}
+		}
+		return printJSON(intervals)
+	}
+	report := activity.Summarize(intervals, by, since, now)
+	if asJSON {
+		return printJSON(report)
+	}
+	fmt.Println(report)
+	return nil
+}
+
+func printJSON(v any) error {
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
@@ -0,0 +1,179 @@
+// Package hooks runs the user commands hyprd.yaml maps to daemon events and lock phases.
+//
+// Responsibilities:
+// - Match events against each hook's class and workspace filters.
+// - Pass the event as JSON on stdin and HYPRD_* environment variables.
+// - Queue hooks behind a shared concurrency limit and bound them by per-hook timeouts.
+// - Run each command in its own process group, killed with its children on timeout.
+package hooks
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"sync"
+	"time"
+
+	"dotfiles/cmds/internal/config"
+	"dotfiles/cmds/internal/hyprd/state"
+)
+
+// Event is one daemon event as hooks receive it.
+type Event struct {
+	Name      string    `json:"event"`
+	Time      time.Time `json:"time"`
+	Workspace int       `json:"workspace"`          // focused workspace unless the event names one
+	Previous  int       `json:"previous,omitempty"` // workspace: the one left
+	Address   string    `json:"address,omitempty"`  // window events
+	Class     string    `json:"class,omitempty"`
+	Title     string    `json:"title,omitempty"`
+	Session   string    `json:"session,omitempty"` // active session on Workspace, or the one opened
+	Project   string    `json:"project,omitempty"` // project path on Workspace
+	Kind      string    `json:"kind,omitempty"`    // lock, unlock: pseudo|full
+	Trigger   string    `json:"trigger,omitempty"` // lock, unlock: what caused it
+	Seconds   int64     `json:"seconds,omitempty"` // unlock: how long the lock lasted
+}
+
+// Env returns the event as HYPRD_* variables; unset fields are left out.
+func (e Event) Env() []string {
+	env := []string{"HYPRD_EVENT=" + e.Name, "HYPRD_WORKSPACE=" + strconv.Itoa(e.Workspace)}
+	add := func(key, value string) {
+		if value != "" {
+			env = append(env, "HYPRD_"+key+"="+value)
+		}
+	}
+	if e.Previous != 0 {
+		add("PREVIOUS", strconv.Itoa(e.Previous))
+	}
+	add("ADDRESS", e.Address)
+	add("CLASS", e.Class)
+	add("TITLE", e.Title)
+	add("SESSION", e.Session)
+	add("PROJECT", e.Project)
+	add("KIND", e.Kind)
+	add("TRIGGER", e.Trigger)
+	if e.Seconds != 0 {
+		add("SECONDS", strconv.FormatInt(e.Seconds, 10))
+	}
+	return env
+}
+
+// hookBacklog bounds the hooks waiting for a free slot; beyond it new runs are dropped.
+const hookBacklog = 64
+
+// Runner fires configured hooks in the background, reading hooks from the live config so
+// edits to hyprd.yaml apply without a restart.
+//
+// Hooks wait in one FIFO queue for the hooks.concurrency slots. A hook still running from
+// an earlier event stays queued until it finishes, so one command never overlaps itself and
+// sees its events in order. Once hookBacklog runs are waiting, further ones are dropped
+// and logged, so a burst of events (e.g. cycling workspaces) can't pile up without bound.
+type Runner struct {
+	state   *state.State
+	mu      sync.Mutex
+	queue   []job
+	limit   int             // hooks.concurrency as of the last Fire
+	busy    int             // workers running a hook
+	running map[string]bool // event+command keys currently running
+}
+
+// job is one queued hook run.
+type job struct {
+	key     string
+	hook    config.EventHook
+	ev      Event
+	timeout time.Duration
+}
+
+func NewRunner(s *state.State) *Runner {
+	return &Runner{state: s, running: make(map[string]bool)}
+}
+
+// Fire fills the event's time and workspace context, then queues every matching hook.
+func (r *Runner) Fire(ev Event) {
+	cfg := r.state.GetConfig().Hooks.WithDefaults()
+	if len(cfg.Events[ev.Name]) == 0 {
+		return
+	}
+	if ev.Time.IsZero() {
+		ev.Time = time.Now()
+	}
+	if ev.Workspace == 0 {
+		ev.Workspace = r.state.GetWorkspace()
+	}
+	if ev.Session == "" {
+		ev.Session = r.state.GetActiveSession(ev.Workspace)
+	}
+	if ev.Project == "" {
+		ev.Project = r.state.GetProjectPath(ev.Workspace)
+	}
+
+	for _, hook := range cfg.Matching(ev.Name, ev.Class, ev.Workspace) {
+		r.enqueue(job{key: ev.Name + "\x00" + hook.Run, hook: hook, ev: ev, timeout: Timeout(hook, cfg)}, cfg.Concurrency)
+	}
+}
+
+// enqueue appends j, or drops it when the backlog is full, and starts workers up to limit.
+func (r *Runner) enqueue(j job, limit int) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.limit = limit
+	if len(r.queue) >= hookBacklog {
+		fmt.Fprintf(os.Stderr, "hyprd hooks: %s: %d hooks queued, dropped %q\n", j.ev.Name, len(r.queue), j.hook.Run)
+		return
+	}
+	r.queue = append(r.queue, j)
+	for r.busy < r.limit {
+		next, ok := r.nextLocked()
+		if !ok {
+			return
+		}
+		r.busy++
+		go r.work(next)
+	}
+}
+
+// work runs j, then keeps taking queued jobs until none is ready or the limit shrank.
+func (r *Runner) work(j job) {
+	for {
+		if out, err := Run(j.hook.Run, j.ev.Env(), j.ev, j.timeout); err != nil {
+			fmt.Fprintf(os.Stderr, "hyprd hooks: %s: %v: %s\n", j.ev.Name, err, bytes.TrimSpace(out))
+		}
+		r.mu.Lock()
+		delete(r.running, j.key)
+		next, ok := job{}, false
+		if r.busy <= r.limit {
+			next, ok = r.nextLocked()
+		}
+		if !ok {
+			r.busy--
+			r.mu.Unlock()
+			return
+		}
+		r.mu.Unlock()
+		j = next
+	}
+}
+
+// nextLocked pops the oldest queued job whose hook isn't already running and marks it
+// running; caller must hold r.mu.
+func (r *Runner) nextLocked() (job, bool) {
+	for i, j := range r.queue {
+		if !r.running[j.key] {
+			r.queue = slices.Delete(r.queue, i, i+1)
+			r.running[j.key] = true
+			return j, true
+		}
+	}
+	return job{}, false
+}
+
+// Timeout is the limit a hook runs under: its own timeout, else hooks.timeout.
+func Timeout(hook config.EventHook, cfg config.HooksConfig) time.Duration {
+	if hook.Timeout > 0 {
+		return time.Duration(hook.Timeout) * time.Second
+	}
+	return time.Duration(cfg.WithDefaults().Timeout) * time.Second
+}
@@ -0,0 +1,116 @@
+package hooks
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"dotfiles/cmds/internal/config"
+)
+
+// stubHook appends "start <workspace>" and "end <workspace>" around a short sleep, so the
+// log shows which runs overlapped.
+func stubHook(log string) string {
+	return fmt.Sprintf(`echo "start $HYPRD_WORKSPACE" >> %[1]s; sleep 0.05; echo "end $HYPRD_WORKSPACE" >> %[1]s`, log)
+}
+
+func queueJob(r *Runner, run string, ws, limit int) {
+	ev := Event{Name: "workspace", Workspace: ws}
+	r.enqueue(job{key: ev.Name + "\x00" + run, hook: config.EventHook{Run: run}, ev: ev, timeout: 5 * time.Second}, limit)
+}
+
+// waitIdle blocks until no hook is running or queued.
+func waitIdle(t *testing.T, r *Runner) {
+	t.Helper()
+	deadline := time.Now().Add(10 * time.Second)
+	for time.Now().Before(deadline) {
+		r.mu.Lock()
+		idle := r.busy == 0 && len(r.queue) == 0
+		r.mu.Unlock()
+		if idle {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatal("hooks still running after 10s")
+}
+
+// readLog returns the log lines and the most runs that were in flight at once.
+func readLog(t *testing.T, path string) ([]string, int) {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	running, peak := 0, 0
+	for _, line := range lines {
+		if strings.HasPrefix(line, "start ") {
+			running++
+			peak = max(peak, running)
+		} else {
+			running--
+		}
+	}
+	return lines, peak
+}
+
+func TestRunnerConcurrencyLimit(t *testing.T) {
+	log := filepath.Join(t.TempDir(), "log")
+	r := NewRunner(nil)
+	for ws := 1; ws <= 6; ws++ {
+		// Distinct commands, so only the limit keeps them apart.
+		queueJob(r, fmt.Sprintf("%s # %d", stubHook(log), ws), ws, 2)
+	}
+	waitIdle(t, r)
+
+	lines, peak := readLog(t, log)
+	if len(lines) != 12 {
+		t.Fatalf("log has %d lines, want 12: %v", len(lines), lines)
+	}
+	if peak != 2 {
+		t.Errorf("peak concurrency %d, want 2", peak)
+	}
+}
+
+func TestRunnerNoSelfOverlap(t *testing.T) {
+	log := filepath.Join(t.TempDir(), "log")
+	r := NewRunner(nil)
+	run := stubHook(log)
+	for ws := 1; ws <= 3; ws++ {
+		queueJob(r, run, ws, 4)
+	}
+	waitIdle(t, r)
+
+	lines, _ := readLog(t, log)
+	want := []string{"start 1", "end 1", "start 2", "end 2", "start 3", "end 3"}
+	if !slices.Equal(lines, want) {
+		t.Errorf("log = %v, want %v", lines, want)
+	}
+}
+
+func TestRunnerDropsPastBacklog(t *testing.T) {
+	dir := t.TempDir()
+	gate := filepath.Join(dir, "go")
+	r := NewRunner(nil)
+	// Hold the only slot until the queue has been filled.
+	queueJob(r, fmt.Sprintf("while [ ! -e %s ]; do sleep 0.01; done", gate), 1, 1)
+	for i := range hookBacklog + 5 {
+		queueJob(r, fmt.Sprintf("true # %d", i), 1, 1)
+	}
+
+	r.mu.Lock()
+	queued := len(r.queue)
+	r.mu.Unlock()
+	if queued != hookBacklog {
+		t.Errorf("queued %d, want %d", queued, hookBacklog)
+	}
+	if err := os.WriteFile(gate, nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	waitIdle(t, r)
+}
@@ -0,0 +1,43 @@
+package hooks
+
+// run.go executes one hook command with its payload and environment.
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"syscall"
+	"time"
+)
+
+// Run executes command with `sh -c`, payload as one JSON line on stdin, and env added to the
+// daemon's environment. It returns the combined output, which is also returned alongside a
+// failure.
+//
+// The command gets its own process group so a timeout kills whatever the shell started too.
+func Run(command string, env []string, payload any, timeout time.Duration) ([]byte, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	cmd := exec.CommandContext(ctx, "sh", "-c", command)
+	cmd.Stdin = bytes.NewReader(append(data, '\n'))
+	cmd.Env = append(os.Environ(), env...)
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+	cmd.Cancel = func() error { return syscall.Kill(-cmd.Process.Pid, syscall.SIGKILL) }
+	cmd.WaitDelay = time.Second
+	out, err := cmd.CombinedOutput()
+	if ctx.Err() != nil {
+		return out, fmt.Errorf("%q: timed out after %s", command, timeout)
+	}
+	if err != nil {
+		return out, fmt.Errorf("%q: %w", command, err)
+	}
+	return out, nil
+}
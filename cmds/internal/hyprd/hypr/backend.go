@@ -0,0 +1,107 @@
+package hypr
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Backend names reported in Capabilities.
+const (
+	BackendLua    = "lua"    // `eval` Lua: hl.dispatch / hl.config
+	BackendLegacy = "legacy" // classic `dispatch` / `keyword` requests
+)
+
+// Mutation is one compositor change in both request dialects.
+//
+// Legacy holds one or more `dispatch …`/`keyword …` requests; several are sent as a
+// single [[BATCH]] so they apply together like one Lua chunk.
+type Mutation struct {
+	Lua    string
+	Legacy []string
+}
+
+// Backend renders mutations into command-socket requests for one Hyprland API.
+type Backend interface {
+	Name() string
+	Request(m Mutation) string
+	Replies(m Mutation) int // "ok" replies a successful Request gets back
+}
+
+type luaBackend struct{}
+
+func (luaBackend) Name() string { return BackendLua }
+
+func (luaBackend) Request(m Mutation) string { return "eval " + m.Lua }
+
+func (luaBackend) Replies(Mutation) int { return 1 }
+
+type legacyBackend struct{}
+
+func (legacyBackend) Name() string { return BackendLegacy }
+
+func (legacyBackend) Request(m Mutation) string {
+	if len(m.Legacy) == 1 {
+		return m.Legacy[0]
+	}
+	return "[[BATCH]]" + strings.Join(m.Legacy, ";")
+}
+
+// Replies is one per request: Hyprland joins a batch's replies, and older builds do so
+// without a separator ("okok").
+func (legacyBackend) Replies(m Mutation) int { return len(m.Legacy) }
+
+// Capabilities is what NewClient learned about the running compositor.
+type Capabilities struct {
+	Version string `json:"version,omitempty"` // release tag from `version`, e.g. "v0.52.0"
+	Commit  string `json:"commit,omitempty"`
+	Backend string `json:"backend"`
+}
+
+// Capabilities reports the compositor version and the mutation backend in use.
+func (c *Client) Capabilities() Capabilities {
+	caps := c.caps
+	caps.Backend = c.backendOrDefault().Name()
+	return caps
+}
+
+func (c *Client) backendOrDefault() Backend {
+	if c.backend == nil {
+		return luaBackend{}
+	}
+	return c.backend
+}
+
+// probe reads the compositor version and picks a backend: Lua when `eval` of an empty
+// chunk is accepted, legacy when Hyprland answers "unknown request". Any other outcome
+// keeps the Lua default, since that is what current builds speak, and is returned.
+func (c *Client) probe() error {
+	if data, err := c.Request("j/version"); err == nil {
+		var v struct {
+			Tag     string `json:"tag"`
+			Version string `json:"version"`
+			Commit  string `json:"commit"`
+		}
+		if json.Unmarshal(data, &v) == nil {
+			c.caps.Version = v.Tag
+			if c.caps.Version == "" {
+				c.caps.Version = v.Version
+			}
+			c.caps.Commit = v.Commit
+		}
+	}
+	c.backend = luaBackend{}
+	resp, err := c.Request("eval -- hyprd probe")
+	if err != nil {
+		return fmt.Errorf("eval probe: %w", err)
+	}
+	switch got := strings.TrimSpace(string(resp)); {
+	case got == "ok":
+		return nil
+	case strings.Contains(strings.ToLower(got), "unknown request"):
+		c.backend = legacyBackend{}
+		return nil
+	default:
+		return fmt.Errorf("eval probe: %s", got)
+	}
+}
@@ -0,0 +1,41 @@
+package hypr
+
+import "testing"
+
+func TestLegacyBackendRequest(t *testing.T) {
+	tests := []struct {
+		name    string
+		legacy  []string
+		want    string
+		replies int
+	}{
+		{"single", []string{"dispatch workspace 3"}, "dispatch workspace 3", 1},
+		{
+			"batch",
+			[]string{"keyword general:gaps_in 4", "keyword general:gaps_out 8"},
+			"[[BATCH]]keyword general:gaps_in 4;keyword general:gaps_out 8",
+			2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := Mutation{Lua: "unused", Legacy: tt.legacy}
+			if got := (legacyBackend{}).Request(m); got != tt.want {
+				t.Errorf("Request = %q, want %q", got, tt.want)
+			}
+			if got := (legacyBackend{}).Replies(m); got != tt.replies {
+				t.Errorf("Replies = %d, want %d", got, tt.replies)
+			}
+		})
+	}
+}
+
+func TestLuaBackendRequest(t *testing.T) {
+	m := Mutation{Lua: `hl.dispatch(hl.dsp.focus({ workspace = 3 }))`, Legacy: []string{"dispatch workspace 3"}}
+	if got, want := (luaBackend{}).Request(m), "eval "+m.Lua; got != want {
+		t.Errorf("Request = %q, want %q", got, want)
+	}
+	if got := (luaBackend{}).Replies(m); got != 1 {
+		t.Errorf("Replies = %d, want 1", got)
+	}
+}
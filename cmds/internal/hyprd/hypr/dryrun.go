@@ -0,0 +1,61 @@
+package hypr
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// Op is one mutation a dry-run Client recorded instead of sending.
+type Op struct {
+	Name   string // method name, e.g. "FocusWindow"; "skip:<what>" for guarded side effects
+	Detail string // the request the backend would have sent, or what was skipped
+}
+
+func (o Op) String() string {
+	if what, ok := strings.CutPrefix(o.Name, "skip:"); ok {
+		return fmt.Sprintf("skip %s: %s", what, o.Detail)
+	}
+	return fmt.Sprintf("%s: %s", o.Name, o.Detail)
+}
+
+// Recorder collects the mutations of a dry-run Client in call order.
+type Recorder struct {
+	mu  sync.Mutex
+	ops []Op
+}
+
+// Ops returns the recorded mutations in order.
+func (r *Recorder) Ops() []Op {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]Op(nil), r.ops...)
+}
+
+func (r *Recorder) add(op Op) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.ops = append(r.ops, op)
+}
+
+// DryRun returns a Client that answers queries from the live socket but records every
+// mutation on the returned Recorder instead of evaluating it.
+func (c *Client) DryRun() (*Client, *Recorder) {
+	r := &Recorder{}
+	return &Client{socketPath: c.socketPath, backend: c.backend, caps: c.caps, recorder: r}, r
+}
+
+// IsDryRun reports whether mutations are being recorded rather than sent.
+func (c *Client) IsDryRun() bool {
+	return c.recorder != nil
+}
+
+// Skip records a side effect outside Hyprland (a spawned process, a written file) and
+// reports whether the caller should skip it because c is a dry-run client.
+func (c *Client) Skip(what, detail string) bool {
+	if c.recorder == nil {
+		return false
+	}
+	c.recorder.add(Op{Name: "skip:" + what, Detail: detail})
+	return true
+}
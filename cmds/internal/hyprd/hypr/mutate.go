@@ -1,25 +1,59 @@
 package hypr
 
-// Mutations go through `eval` Lua (`hl.dispatch` / `hl.config` / …), not legacy dispatch/keyword.
+// Mutations are written as `eval` Lua (`hl.dispatch` / `hl.config` / …) with the classic
+// dispatch/keyword form alongside; the client's Backend picks which one is sent.
 
 import (
 	"fmt"
 	"strings"
 )
 
-// eval sends `eval <lua>` on the request socket.
-// Success is a response of exactly "ok" after trimming whitespace.
-func (c *Client) eval(op, lua string) error {
-	resp, err := c.Request("eval " + lua)
+// mutate sends m through the client's backend.
+// Success is every whitespace-separated response field being "ok" (one per batched request).
+// A dry-run client records the request and reports success without sending it.
+func (c *Client) mutate(op string, m Mutation) error {
+	backend := c.backendOrDefault()
+	req := backend.Request(m)
+	if c.recorder != nil {
+		c.recorder.add(Op{Name: op, Detail: req})
+		return nil
+	}
+	resp, err := c.Request(req)
 	if err != nil {
 		return fmt.Errorf("%s: %w", op, err)
 	}
-	if got := strings.TrimSpace(string(resp)); got != "ok" {
+	got := strings.TrimSpace(string(resp))
+	if got == "" {
+		return fmt.Errorf("%s: empty response", op)
+	}
+	if !allOK(got, backend.Replies(m)) {
 		return fmt.Errorf("%s: %s", op, got)
 	}
 	return nil
 }
 
+// allOK reports whether reply is exactly n "ok"s, with or without whitespace between them.
+func allOK(reply string, n int) bool {
+	for range n {
+		var ok bool
+		if reply, ok = strings.CutPrefix(strings.TrimSpace(reply), "ok"); !ok {
+			return false
+		}
+	}
+	return strings.TrimSpace(reply) == ""
+}
+
+func dispatch(format string, args ...any) string {
+	return "dispatch " + fmt.Sprintf(format, args...)
+}
+
+func silentSuffix(follow bool) string {
+	if follow {
+		return ""
+	}
+	return "silent"
+}
+
 // luaQuote returns a Lua double-quoted string literal.
 func luaQuote(s string) string {
 	var b strings.Builder
@@ -58,86 +92,136 @@ func windowAddress(address string) string {
 
 // FocusWorkspace focuses workspace id.
 func (c *Client) FocusWorkspace(id int) error {
-	return c.eval("FocusWorkspace", fmt.Sprintf(
-		"hl.dispatch(hl.dsp.focus({ workspace = %d }))", id,
-	))
+	return c.mutate("FocusWorkspace", Mutation{
+		Lua: fmt.Sprintf(
+			"hl.dispatch(hl.dsp.focus({ workspace = %d }))", id,
+		),
+		Legacy: []string{dispatch("workspace %d", id)},
+	})
 }
 
 // FocusWindow focuses the window at the raw hex address.
 func (c *Client) FocusWindow(address string) error {
-	return c.eval("FocusWindow", fmt.Sprintf(
-		"hl.dispatch(hl.dsp.focus({ window = %s }))",
-		luaQuote(windowAddress(address)),
-	))
+	return c.mutate("FocusWindow", Mutation{
+		Lua: fmt.Sprintf(
+			"hl.dispatch(hl.dsp.focus({ window = %s }))",
+			luaQuote(windowAddress(address)),
+		),
+		Legacy: []string{dispatch("focuswindow %s", windowAddress(address))},
+	})
 }
 
 // MoveActiveToWorkspace moves the active window to workspace id.
 // follow=false is the old silent move.
 func (c *Client) MoveActiveToWorkspace(id int, follow bool) error {
-	return c.eval("MoveActiveToWorkspace", fmt.Sprintf(
-		"hl.dispatch(hl.dsp.window.move({ workspace = %d, follow = %s }))",
-		id, luaBool(follow),
-	))
+	return c.mutate("MoveActiveToWorkspace", Mutation{
+		Lua: fmt.Sprintf(
+			"hl.dispatch(hl.dsp.window.move({ workspace = %d, follow = %s }))",
+			id, luaBool(follow),
+		),
+		Legacy: []string{dispatch("movetoworkspace%s %d", silentSuffix(follow), id)},
+	})
 }
 
 // MoveWindowToWorkspace moves window address to workspace (selector string).
 // workspace may be "3", "special:shadow", or a name. follow=false is silent.
 func (c *Client) MoveWindowToWorkspace(address string, workspace string, follow bool) error {
-	return c.eval("MoveWindowToWorkspace", fmt.Sprintf(
-		"hl.dispatch(hl.dsp.window.move({ workspace = %s, follow = %s, window = %s }))",
-		luaQuote(workspace), luaBool(follow), luaQuote(windowAddress(address)),
-	))
+	return c.mutate("MoveWindowToWorkspace", Mutation{
+		Lua: fmt.Sprintf(
+			"hl.dispatch(hl.dsp.window.move({ workspace = %s, follow = %s, window = %s }))",
+			luaQuote(workspace), luaBool(follow), luaQuote(windowAddress(address)),
+		),
+		Legacy: []string{dispatch("movetoworkspace%s %s,%s", silentSuffix(follow), workspace, windowAddress(address))},
+	})
 }
 
 // ToggleFloatActive toggles floating on the active window.
 func (c *Client) ToggleFloatActive() error {
 	// Hyprland default config uses action = "toggle"; bare float() is ambiguous.
-	return c.eval("ToggleFloatActive",
-		`hl.dispatch(hl.dsp.window.float({ action = "toggle" }))`,
-	)
+	return c.mutate("ToggleFloatActive", Mutation{
+		Lua:    `hl.dispatch(hl.dsp.window.float({ action = "toggle" }))`,
+		Legacy: []string{dispatch("togglefloating")},
+	})
 }
 
 // ResizeActiveExact resizes the active window to exact pixel size w×h.
 func (c *Client) ResizeActiveExact(w, h int) error {
-	return c.eval("ResizeActiveExact", fmt.Sprintf(
-		"hl.dispatch(hl.dsp.window.resize({ x = %d, y = %d }))", w, h,
-	))
+	return c.mutate("ResizeActiveExact", Mutation{
+		Lua: fmt.Sprintf(
+			"hl.dispatch(hl.dsp.window.resize({ x = %d, y = %d }))", w, h,
+		),
+		Legacy: []string{dispatch("resizeactive exact %d %d", w, h)},
+	})
 }
 
 // MoveActiveRelative moves the active window by (dx, dy) pixels.
 func (c *Client) MoveActiveRelative(dx, dy int) error {
-	return c.eval("MoveActiveRelative", fmt.Sprintf(
-		"hl.dispatch(hl.dsp.window.move({ x = %d, y = %d, relative = true }))",
-		dx, dy,
-	))
+	return c.mutate("MoveActiveRelative", Mutation{
+		Lua: fmt.Sprintf(
+			"hl.dispatch(hl.dsp.window.move({ x = %d, y = %d, relative = true }))",
+			dx, dy,
+		),
+		Legacy: []string{dispatch("moveactive %d %d", dx, dy)},
+	})
+}
+
+// MoveWindowExact moves window address to absolute layout coordinates (x, y) without focusing it.
+func (c *Client) MoveWindowExact(address string, x, y int) error {
+	return c.mutate("MoveWindowExact", Mutation{
+		Lua: fmt.Sprintf(
+			"hl.dispatch(hl.dsp.window.move({ x = %d, y = %d, window = %s }))",
+			x, y, luaQuote(windowAddress(address)),
+		),
+		Legacy: []string{dispatch("movewindowpixel exact %d %d,%s", x, y, windowAddress(address))},
+	})
+}
+
+// TogglePinActive toggles pinning (show on every workspace) for the active floating window.
+func (c *Client) TogglePinActive() error {
+	return c.mutate("TogglePinActive", Mutation{
+		Lua:    `hl.dispatch(hl.dsp.window.pin({ action = "toggle" }))`,
+		Legacy: []string{dispatch("pin")},
+	})
 }
 
 // MoveWindowDirection moves the active window in dir ("left"|"right"|"up"|"down").
 func (c *Client) MoveWindowDirection(dir string) error {
-	return c.eval("MoveWindowDirection", fmt.Sprintf(
-		"hl.dispatch(hl.dsp.window.move({ direction = %s }))",
-		luaQuote(dir),
-	))
+	return c.mutate("MoveWindowDirection", Mutation{
+		Lua: fmt.Sprintf(
+			"hl.dispatch(hl.dsp.window.move({ direction = %s }))",
+			luaQuote(dir),
+		),
+		Legacy: []string{dispatch("movewindow %.1s", dir)},
+	})
 }
 
 // CenterActive centers the active window.
 func (c *Client) CenterActive() error {
-	return c.eval("CenterActive", `hl.dispatch(hl.dsp.window.center())`)
+	return c.mutate("CenterActive", Mutation{
+		Lua:    `hl.dispatch(hl.dsp.window.center())`,
+		Legacy: []string{dispatch("centerwindow")},
+	})
 }
 
 // CloseWindow closes the window at address.
 func (c *Client) CloseWindow(address string) error {
-	return c.eval("CloseWindow", fmt.Sprintf(
-		"hl.dispatch(hl.dsp.window.close({ window = %s }))",
-		luaQuote(windowAddress(address)),
-	))
+	return c.mutate("CloseWindow", Mutation{
+		Lua: fmt.Sprintf(
+			"hl.dispatch(hl.dsp.window.close({ window = %s }))",
+			luaQuote(windowAddress(address)),
+		),
+		Legacy: []string{dispatch("closewindow %s", windowAddress(address))},
+	})
 }
 
 // Exec runs cmd via Hyprland's exec dispatcher.
 func (c *Client) Exec(cmd string) error {
-	return c.eval("Exec", fmt.Sprintf(
-		"hl.dispatch(hl.dsp.exec_cmd(%s))", luaQuote(cmd),
-	))
+	return c.mutate("Exec", Mutation{
+		Lua: fmt.Sprintf(
+			"hl.dispatch(hl.dsp.exec_cmd(%s))", luaQuote(cmd),
+		),
+		Legacy: []string{dispatch("exec %s", cmd)},
+	})
 }
 
 // ExecOnWorkspace runs cmd, placing the new window on workspace.
@@ -147,62 +231,86 @@ func (c *Client) ExecOnWorkspace(cmd string, workspace int, silent bool) error {
 	if silent {
 		ws += " silent"
 	}
-	return c.eval("ExecOnWorkspace", fmt.Sprintf(
-		"hl.dispatch(hl.dsp.exec_cmd(%s, { workspace = %s }))",
-		luaQuote(cmd), luaQuote(ws),
-	))
+	return c.mutate("ExecOnWorkspace", Mutation{
+		Lua: fmt.Sprintf(
+			"hl.dispatch(hl.dsp.exec_cmd(%s, { workspace = %s }))",
+			luaQuote(cmd), luaQuote(ws),
+		),
+		Legacy: []string{dispatch("exec [workspace %s] %s", ws, cmd)},
+	})
 }
 
 // Submap enters named submap; "reset" leaves the current submap.
 func (c *Client) Submap(name string) error {
-	return c.eval("Submap", fmt.Sprintf(
-		"hl.dispatch(hl.dsp.submap(%s))", luaQuote(name),
-	))
+	return c.mutate("Submap", Mutation{
+		Lua: fmt.Sprintf(
+			"hl.dispatch(hl.dsp.submap(%s))", luaQuote(name),
+		),
+		Legacy: []string{dispatch("submap %s", name)},
+	})
 }
 
 // ToggleSpecialWorkspace toggles the named special workspace.
 func (c *Client) ToggleSpecialWorkspace(name string) error {
-	return c.eval("ToggleSpecialWorkspace", fmt.Sprintf(
-		"hl.dispatch(hl.dsp.workspace.toggle_special(%s))", luaQuote(name),
-	))
+	return c.mutate("ToggleSpecialWorkspace", Mutation{
+		Lua: fmt.Sprintf(
+			"hl.dispatch(hl.dsp.workspace.toggle_special(%s))", luaQuote(name),
+		),
+		Legacy: []string{dispatch("togglespecialworkspace %s", name)},
+	})
 }
 
 // LayoutMsg sends a layoutmsg string (e.g. "swapwithmaster master").
 func (c *Client) LayoutMsg(msg string) error {
-	return c.eval("LayoutMsg", fmt.Sprintf(
-		"hl.dispatch(hl.dsp.layout(%s))", luaQuote(msg),
-	))
+	return c.mutate("LayoutMsg", Mutation{
+		Lua: fmt.Sprintf(
+			"hl.dispatch(hl.dsp.layout(%s))", luaQuote(msg),
+		),
+		Legacy: []string{dispatch("layoutmsg %s", msg)},
+	})
 }
 
 // MoveCursor warps the cursor to absolute (x, y).
 func (c *Client) MoveCursor(x, y int) error {
-	return c.eval("MoveCursor", fmt.Sprintf(
-		"hl.dispatch(hl.dsp.cursor.move({ x = %d, y = %d }))", x, y,
-	))
+	return c.mutate("MoveCursor", Mutation{
+		Lua: fmt.Sprintf(
+			"hl.dispatch(hl.dsp.cursor.move({ x = %d, y = %d }))", x, y,
+		),
+		Legacy: []string{dispatch("movecursor %d %d", x, y)},
+	})
 }
 
 // SetAccent sets active border and shadow colors (rgba(...) strings).
 func (c *Client) SetAccent(border, shadow string) error {
-	return c.eval("SetAccent", fmt.Sprintf(
-		"hl.config({ general = { col = { active_border = %s } }, decoration = { shadow = { color = %s } } })",
-		luaQuote(border), luaQuote(shadow),
-	))
+	return c.mutate("SetAccent", Mutation{
+		Lua: fmt.Sprintf(
+			"hl.config({ general = { col = { active_border = %s } }, decoration = { shadow = { color = %s } } })",
+			luaQuote(border), luaQuote(shadow),
+		),
+		Legacy: []string{"keyword general:col.active_border " + border, "keyword decoration:shadow:color " + shadow},
+	})
 }
 
 // SetOuterGaps sets general.gaps_out (top, right, bottom, left).
 func (c *Client) SetOuterGaps(top, right, bottom, left int) error {
-	return c.eval("SetOuterGaps", fmt.Sprintf(
-		"hl.config({ general = { gaps_out = { top = %d, right = %d, bottom = %d, left = %d } } })",
-		top, right, bottom, left,
-	))
+	return c.mutate("SetOuterGaps", Mutation{
+		Lua: fmt.Sprintf(
+			"hl.config({ general = { gaps_out = { top = %d, right = %d, bottom = %d, left = %d } } })",
+			top, right, bottom, left,
+		),
+		Legacy: []string{fmt.Sprintf("keyword general:gaps_out %d,%d,%d,%d", top, right, bottom, left)},
+	})
 }
 
 // SetWorkspaceAnim sets the workspaces animation style ("slide"|"slidevert").
 func (c *Client) SetWorkspaceAnim(style string) error {
-	return c.eval("SetWorkspaceAnim", fmt.Sprintf(
-		`hl.animation({ leaf = "workspaces", enabled = true, speed = 3, bezier = "default", style = %s })`,
-		luaQuote(style),
-	))
+	return c.mutate("SetWorkspaceAnim", Mutation{
+		Lua: fmt.Sprintf(
+			`hl.animation({ leaf = "workspaces", enabled = true, speed = 3, bezier = "default", style = %s })`,
+			luaQuote(style),
+		),
+		Legacy: []string{"keyword animation workspaces,1,3,default," + style},
+	})
 }
 
 // AddFadeRule adds a dynamic window rule with animation = "fade".
@@ -212,7 +320,14 @@ func (c *Client) AddFadeRule(class, initialTitle string) error {
 	if initialTitle != "" {
 		match += ", initial_title = " + luaQuote(initialTitle)
 	}
-	return c.eval("AddFadeRule", fmt.Sprintf(
-		`hl.window_rule({ match = { %s }, animation = "fade" })`, match,
-	))
+	legacyMatch := "class:" + class
+	if initialTitle != "" {
+		legacyMatch += ",initialTitle:" + initialTitle
+	}
+	return c.mutate("AddFadeRule", Mutation{
+		Lua: fmt.Sprintf(
+			`hl.window_rule({ match = { %s }, animation = "fade" })`, match,
+		),
+		Legacy: []string{"keyword windowrulev2 animation fade," + legacyMatch},
+	})
 }
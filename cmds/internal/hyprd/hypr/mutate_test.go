@@ -0,0 +1,26 @@
+package hypr
+
+import "testing"
+
+func TestAllOK(t *testing.T) {
+	tests := []struct {
+		reply string
+		n     int
+		want  bool
+	}{
+		{"ok", 1, true},
+		{"ok ok", 2, true},
+		{"okok", 2, true}, // legacy batch replies joined without a separator
+		{"ok\n\nok\n", 2, true},
+		{"okerr", 2, false},
+		{"ok invalid dispatcher", 2, false},
+		{"ok", 2, false}, // short count
+		{"okok", 1, false},
+		{"", 1, false},
+	}
+	for _, tt := range tests {
+		if got := allOK(tt.reply, tt.n); got != tt.want {
+			t.Errorf("allOK(%q, %d) = %v, want %v", tt.reply, tt.n, got, tt.want)
+		}
+	}
+}
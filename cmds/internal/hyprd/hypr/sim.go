@@ -0,0 +1,152 @@
+package hypr
+
+import (
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// Sim is a simulated Hyprland command socket for `hyprd replay`.
+//
+// Queries are answered from the last snapshot set with SetClients/SetWorkspace; mutations
+// (eval) are acknowledged and logged, not applied, since the trace's next snapshot already
+// shows their effect.
+type Sim struct {
+	dir      string
+	listener net.Listener
+
+	mu         sync.Mutex
+	clients    []Window
+	workspace  int
+	monitors   []Monitor
+	dispatched []string
+}
+
+// NewSim listens on a command socket in a fresh temporary directory.
+func NewSim() (*Sim, error) {
+	dir, err := os.MkdirTemp("", "hyprd-sim-")
+	if err != nil {
+		return nil, err
+	}
+	listener, err := net.Listen("unix", filepath.Join(dir, ".socket.sock"))
+	if err != nil {
+		os.RemoveAll(dir)
+		return nil, err
+	}
+	s := &Sim{dir: dir, listener: listener, workspace: 1}
+	go s.serve()
+	return s, nil
+}
+
+// Client returns a Client bound to the simulated socket.
+func (s *Sim) Client() *Client {
+	return &Client{socketPath: filepath.Join(s.dir, ".socket.sock")}
+}
+
+// Close stops serving and removes the socket directory.
+func (s *Sim) Close() error {
+	err := s.listener.Close()
+	os.RemoveAll(s.dir)
+	return err
+}
+
+func (s *Sim) SetClients(clients []Window) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.clients = clients
+}
+
+func (s *Sim) SetWorkspace(ws int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.workspace = ws
+}
+
+func (s *Sim) SetMonitors(monitors []Monitor) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.monitors = monitors
+}
+
+// Dispatched returns the mutations hyprd sent, in order.
+func (s *Sim) Dispatched() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.dispatched...)
+}
+
+func (s *Sim) serve() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		go func() {
+			defer conn.Close()
+			buf := make([]byte, 64*1024)
+			n, err := conn.Read(buf)
+			if err != nil {
+				return
+			}
+			conn.Write(s.respond(string(buf[:n])))
+		}()
+	}
+}
+
+func (s *Sim) respond(command string) []byte {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch command {
+	case "j/clients":
+		return mustJSON(s.clients)
+	case "j/activeworkspace":
+		return mustJSON(WsRef{ID: s.workspace, Name: fmt.Sprint(s.workspace)})
+	case "j/activewindow":
+		for _, c := range s.clients {
+			if c.FocusHistoryID == 0 {
+				return mustJSON(c)
+			}
+		}
+		return []byte("{}")
+	case "j/monitors":
+		monitors := slices.Clone(s.monitors)
+		if len(monitors) == 0 {
+			monitors = []Monitor{{Name: "SIM-1", Width: 1920, Height: 1080, Scale: 1, Focused: true}}
+		}
+		for i := range monitors {
+			if monitors[i].Focused {
+				monitors[i].ActiveWS = WsRef{ID: s.workspace, Name: fmt.Sprint(s.workspace)}
+			}
+		}
+		return mustJSON(monitors)
+	case "j/cursorpos":
+		return []byte(`{"x":0,"y":0}`)
+	}
+	if lua, ok := strings.CutPrefix(command, "eval "); ok {
+		s.dispatched = append(s.dispatched, lua)
+		return []byte("ok")
+	}
+	if batch, ok := strings.CutPrefix(command, "[[BATCH]]"); ok {
+		s.dispatched = append(s.dispatched, command)
+		return []byte(strings.Repeat("ok", strings.Count(batch, ";")+1)) // joined like legacy builds
+	}
+	if strings.HasPrefix(command, "dispatch ") || strings.HasPrefix(command, "keyword ") {
+		s.dispatched = append(s.dispatched, command)
+		return []byte("ok")
+	}
+	return []byte("unknown request")
+}
+
+func mustJSON(v any) []byte {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return []byte("null")
+	}
+	return data
+}
@@ -14,9 +14,13 @@ import (
 // Client communicates with Hyprland via its Unix sockets.
 type Client struct {
 	socketPath string
+	backend    Backend      // chosen by probe; nil means Lua
+	caps       Capabilities // compositor version from probe
+	recorder   *Recorder    // set on dry-run clients; see DryRun
 }
 
-// NewClient resolves the command socket from HYPRLAND_INSTANCE_SIGNATURE.
+// NewClient resolves the command socket from HYPRLAND_INSTANCE_SIGNATURE and probes the
+// compositor for the mutation backend it supports.
 func NewClient() (*Client, error) {
 	sig := os.Getenv("HYPRLAND_INSTANCE_SIGNATURE")
 	if sig == "" {
@@ -33,7 +37,11 @@ func NewClient() (*Client, error) {
 		return nil, fmt.Errorf("socket not found: %s", socketPath)
 	}
 
-	return &Client{socketPath: socketPath}, nil
+	c := &Client{socketPath: socketPath}
+	if err := c.probe(); err != nil {
+		fmt.Fprintf(os.Stderr, "hyprd: backend probe: %v (using %s)\n", err, BackendLua)
+	}
+	return c, nil
 }
 
 // EventSocketPath returns the path to the event-streaming socket.
@@ -70,6 +78,7 @@ type Window struct {
 	Workspace      WsRef  `json:"workspace"`
 	Floating       bool   `json:"floating"`
 	Pinned         bool   `json:"pinned"`
+	Fullscreen     int    `json:"fullscreen"` // 0 none, 1 maximized, 2 fullscreen, 3 both
 	Class          string `json:"class"`
 	InitialClass   string `json:"initialClass"`
 	Title          string `json:"title"`
@@ -132,20 +141,44 @@ func (c *Client) ActiveWindow() (*Window, error) {
 	return &w, nil
 }
 
+// CursorPos returns the cursor position in layout coordinates from `hyprctl -j cursorpos`.
+func (c *Client) CursorPos() (x, y int, err error) {
+	data, err := c.Request("j/cursorpos")
+	if err != nil {
+		return 0, 0, err
+	}
+
+	var pos struct {
+		X int `json:"x"`
+		Y int `json:"y"`
+	}
+	if err := json.Unmarshal(data, &pos); err != nil {
+		return 0, 0, fmt.Errorf("parse cursorpos: %w", err)
+	}
+	return pos.X, pos.Y, nil
+}
+
 // Monitor mirrors the JSON from `hyprctl -j monitors`.
-//
-// TODO: reserved tags are wrong; Hyprland returns `reserved` as a [L,T,R,B] array.
 type Monitor struct {
-	ID            int    `json:"id"`
-	Name          string `json:"name"`
-	Width         int    `json:"width"`
-	Height        int    `json:"height"`
-	X             int    `json:"x"`
-	Y             int    `json:"y"`
-	Focused       bool   `json:"focused"`
-	ActiveWS      WsRef  `json:"activeWorkspace"`
-	ReservedTop   int    `json:"reserved"`
-	ReservedRight int    `json:"reservedB"`
+	ID          int     `json:"id"`
+	Name        string  `json:"name"`
+	Description string  `json:"description"`
+	Width       int     `json:"width"`
+	Height      int     `json:"height"`
+	X           int     `json:"x"`
+	Y           int     `json:"y"`
+	Focused     bool    `json:"focused"`
+	ActiveWS    WsRef   `json:"activeWorkspace"`
+	Scale       float64 `json:"scale"`
+	Reserved    [4]int  `json:"reserved"` // [left, top, right, bottom] in layout pixels
+}
+
+// LogicalSize returns the monitor size in layout coordinates (pixels divided by scale).
+func (m Monitor) LogicalSize() (w, h int) {
+	if m.Scale <= 0 {
+		return m.Width, m.Height
+	}
+	return int(float64(m.Width) / m.Scale), int(float64(m.Height) / m.Scale)
 }
 
 // Monitors returns all monitors from `hyprctl -j monitors`.
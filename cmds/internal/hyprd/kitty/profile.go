@@ -34,6 +34,22 @@ func detectTabProfile(cfg *config.HyprConfig, win OSWindow) string {
 	return "editor"
 }
 
+// DetectProfile returns the tab profile owning the Kitty instance at pid, read from its live tab IDs.
+func DetectProfile(cfg *config.HyprConfig, pid int) (string, error) {
+	windows, err := NewClient(pid).FullState()
+	if err != nil {
+		return "", err
+	}
+	if len(windows) == 0 {
+		return "", fmt.Errorf("kitty %d has no OS windows", pid)
+	}
+	win, err := focusedOSWindow(windows)
+	if err != nil {
+		win = windows[0]
+	}
+	return detectTabProfile(cfg, win), nil
+}
+
 // resolveTabAlias picks the profile-specific name from a colon-separated alias using profile.Order.
 func resolveTabAlias(cfg *config.HyprConfig, alias, profileName string) string {
 	if !strings.Contains(alias, ":") {
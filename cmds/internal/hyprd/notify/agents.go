@@ -0,0 +1,278 @@
+package notify
+
+// agents.go keeps a live registry of OpenCode sessions, one per kitty pane, fed by the same
+// events that drive agent notifications.
+
+import (
+	"cmp"
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Agent statuses. Everything except AgentWorking is waiting on the user.
+const (
+	AgentWorking    = "working"
+	AgentComplete   = "complete"
+	AgentIdle       = "idle"
+	AgentPermission = "permission"
+	AgentQuestion   = "question"
+	AgentError      = "error"
+)
+
+// agentStale drops sessions with no events for this long, in case the pane closed without
+// its kitty exiting.
+const agentStale = 24 * time.Hour
+
+// agentWaitRank orders waiting sessions for next-waiting: blocked on a decision first.
+var agentWaitRank = map[string]int{
+	AgentPermission: 0,
+	AgentQuestion:   1,
+	AgentError:      2,
+	AgentComplete:   3,
+	AgentIdle:       4,
+}
+
+// AgentSession is one OpenCode pane as published on the `agents` topic.
+type AgentSession struct {
+	KittyPID      int    `json:"kitty_pid"`
+	KittyWindowID int    `json:"kitty_window_id"`
+	TabID         string `json:"tab_id,omitempty"`
+	App           string `json:"app,omitempty"` // tab icon, as used for the notification app name
+	Workspace     int    `json:"workspace"`
+	Status        string `json:"status"`
+	Message       string `json:"message,omitempty"`
+	Since         int64  `json:"since"`   // unix seconds when Status was entered
+	Elapsed       int64  `json:"elapsed"` // seconds in Status when the snapshot was taken
+	Seen          bool   `json:"seen"`    // pane viewed or jumped to since the last status change
+	updated       time.Time
+}
+
+// Waiting reports whether the session is blocked on, or done and waiting for, the user.
+func (a AgentSession) Waiting() bool {
+	_, ok := agentWaitRank[a.Status]
+	return ok
+}
+
+func (a AgentSession) context() *kittyContext {
+	return &kittyContext{PID: a.KittyPID, WindowID: a.KittyWindowID, TabID: a.TabID, WorkspaceID: a.Workspace, App: a.App}
+}
+
+type agentKey struct {
+	PID      int
+	WindowID int
+}
+
+type agentRegistry struct {
+	mu       sync.Mutex
+	sessions map[agentKey]*AgentSession
+	publish  func([]AgentSession)
+}
+
+var globalAgents = &agentRegistry{sessions: make(map[agentKey]*AgentSession)}
+
+// SetAgentPublish registers a callback that receives the session list after every change.
+func SetAgentPublish(fn func([]AgentSession)) {
+	globalAgents.mu.Lock()
+	globalAgents.publish = fn
+	globalAgents.mu.Unlock()
+}
+
+// AgentSessions returns live sessions ordered by workspace, then kitty PID and window, the
+// numbering `hyprd agents jump <n>` uses.
+func AgentSessions() []AgentSession {
+	return globalAgents.snapshot(time.Now())
+}
+
+// agentStatus maps an OpenCode event to a session status; "" leaves the status alone.
+func agentStatus(event string) string {
+	switch event {
+	case "start", "subagent", "todo-complete":
+		return AgentWorking
+	case "complete":
+		return AgentComplete
+	case "idle":
+		return AgentIdle
+	case "permission":
+		return AgentPermission
+	case "question":
+		return AgentQuestion
+	case "error":
+		return AgentError
+	default:
+		return ""
+	}
+}
+
+// record applies one OpenCode event and publishes the list if anything changed.
+func (r *agentRegistry) record(req NotifyRequest, ctx *kittyContext) {
+	if r.apply(req, ctx, time.Now()) {
+		r.notify()
+	}
+}
+
+// apply updates the pane's session. A repeated status keeps its Since so time in state
+// survives progress events like subagent and todo-complete.
+func (r *agentRegistry) apply(req NotifyRequest, ctx *kittyContext, now time.Time) bool {
+	if paneNotificationID(ctx) == 0 {
+		return false
+	}
+	key := agentKey{ctx.PID, ctx.WindowID}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	s := r.sessions[key]
+	if req.Event == "viewed" {
+		if s == nil || s.Seen {
+			return false
+		}
+		s.Seen = true
+		return true
+	}
+	status := agentStatus(req.Event)
+	if status == "" {
+		return false
+	}
+	if s == nil {
+		s = &AgentSession{KittyPID: ctx.PID, KittyWindowID: ctx.WindowID}
+		r.sessions[key] = s
+	}
+	if s.Status != status {
+		s.Status = status
+		s.Since = now.Unix()
+		s.Seen = false
+	}
+	s.TabID = cmp.Or(ctx.TabID, s.TabID)
+	s.App = cmp.Or(ctx.App, s.App)
+	s.Workspace = cmp.Or(ctx.WorkspaceID, s.Workspace)
+	message := req.Message
+	if req.Event == "complete" {
+		message = req.LastAssistantMessage
+	}
+	if m := preferredSummary(message, "", 120); m != "" {
+		s.Message = m
+	}
+	s.updated = now
+	return true
+}
+
+// markSeen flags a session as viewed after a jump.
+func (r *agentRegistry) markSeen(key agentKey) {
+	r.mu.Lock()
+	s := r.sessions[key]
+	changed := s != nil && !s.Seen
+	if changed {
+		s.Seen = true
+	}
+	r.mu.Unlock()
+	if changed {
+		r.notify()
+	}
+}
+
+// notify sends the current list to the publish callback outside mu.
+func (r *agentRegistry) notify() {
+	r.mu.Lock()
+	publish := r.publish
+	list := r.snapshotLocked(time.Now())
+	r.mu.Unlock()
+	if publish != nil {
+		publish(list)
+	}
+}
+
+func (r *agentRegistry) snapshot(now time.Time) []AgentSession {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.snapshotLocked(now)
+}
+
+// snapshotLocked prunes sessions whose kitty exited or that went quiet, then copies the rest.
+func (r *agentRegistry) snapshotLocked(now time.Time) []AgentSession {
+	list := make([]AgentSession, 0, len(r.sessions))
+	for key, s := range r.sessions {
+		if now.Sub(s.updated) > agentStale || !processAlive(key.PID) {
+			delete(r.sessions, key)
+			continue
+		}
+		entry := *s
+		entry.Elapsed = now.Unix() - s.Since
+		list = append(list, entry)
+	}
+	slices.SortFunc(list, func(a, b AgentSession) int {
+		return cmp.Or(
+			cmp.Compare(a.Workspace, b.Workspace),
+			cmp.Compare(a.KittyPID, b.KittyPID),
+			cmp.Compare(a.KittyWindowID, b.KittyWindowID),
+		)
+	})
+	return list
+}
+
+func processAlive(pid int) bool {
+	_, err := os.Stat("/proc/" + strconv.Itoa(pid))
+	return err == nil
+}
+
+// JumpAgent focuses session n (1-based) from AgentSessions.
+func (n *Notifier) JumpAgent(index int) (string, error) {
+	list := AgentSessions()
+	if index < 1 || index > len(list) {
+		return "", fmt.Errorf("no agent session %d (%d tracked)", index, len(list))
+	}
+	return n.jumpAgent(list[index-1]), nil
+}
+
+// JumpNextWaiting focuses the waiting session that most needs attention: unseen before
+// seen, then permission > question > error > complete > idle, then longest waiting.
+func (n *Notifier) JumpNextWaiting() (string, error) {
+	var waiting []AgentSession
+	for _, s := range AgentSessions() {
+		if s.Waiting() {
+			waiting = append(waiting, s)
+		}
+	}
+	if len(waiting) == 0 {
+		return "", fmt.Errorf("no agent is waiting")
+	}
+	best := slices.MinFunc(waiting, func(a, b AgentSession) int {
+		if a.Seen != b.Seen {
+			if a.Seen {
+				return 1
+			}
+			return -1
+		}
+		return cmp.Or(cmp.Compare(agentWaitRank[a.Status], agentWaitRank[b.Status]), cmp.Compare(a.Since, b.Since))
+	})
+	return n.jumpAgent(best), nil
+}
+
+// jumpAgent focuses the pane and treats the jump as viewing it, clearing its notifications.
+func (n *Notifier) jumpAgent(s AgentSession) string {
+	ctx := s.context()
+	n.focusContext(ctx)
+	n.acknowledgePane(ctx)
+	globalAgents.markSeen(agentKey{s.KittyPID, s.KittyWindowID})
+	return fmt.Sprintf("agents: ws%d %s %s", s.Workspace, s.App, s.Status)
+}
+
+// FormatAgents renders the jump list: index, workspace, status, time in state, message.
+func FormatAgents(list []AgentSession) string {
+	if len(list) == 0 {
+		return "no agent sessions"
+	}
+	var b strings.Builder
+	for i, s := range list {
+		mark := " "
+		if s.Waiting() && !s.Seen {
+			mark = "*"
+		}
+		fmt.Fprintf(&b, "%s%2d  ws%d  %-3s %-10s %6s  %s\n", mark, i+1, s.Workspace, s.App, s.Status,
+			(time.Duration(s.Elapsed) * time.Second).String(), s.Message)
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
@@ -45,6 +45,8 @@ var globalIdleGate = struct {
 
 var soundQueue = make(chan soundRequest, 32)
 
+const shareRedactedTitle = "Hidden while sharing"
+
 type soundRequest struct {
 	path   string
 	volume int
@@ -138,6 +140,7 @@ func (n *Notifier) handleOpencode(req NotifyRequest) error {
 		}
 		n.trackAgentActivity(req, ctx)
 		n.acknowledgePane(ctx)
+		globalAgents.record(req, ctx)
 		return nil
 	}
 
@@ -146,6 +149,7 @@ func (n *Notifier) handleOpencode(req NotifyRequest) error {
 		return nil
 	}
 	n.trackAgentActivity(req, ctx)
+	globalAgents.record(req, ctx)
 
 	switch req.Event {
 	case "start":
@@ -255,6 +259,9 @@ func (n *Notifier) handleDunst(req NotifyRequest) error {
 	switch req.Event {
 	case "script":
 		n.rememberDunstNotification(req)
+		if mode := n.shareMode(); mode != "" {
+			return n.downgradeDunst(req, mode)
+		}
 		sound := n.soundForDunst(req)
 		if sound != "" {
 			if err := n.playSound(sound, n.cfg.Notify.DefaultVolume); err != nil {
@@ -272,6 +279,12 @@ func (n *Notifier) dispatch(spec notificationSpec, ctx *kittyContext) error {
 	if spec.Delay > 0 {
 		time.Sleep(spec.Delay)
 	}
+	if mode := n.shareMode(); mode != "" {
+		if mode == "downgrade" {
+			spec = redactSpec(spec)
+		}
+		return n.sendDunst(spec, ctx)
+	}
 
 	sound := spec.Sound
 	volume := spec.Volume
@@ -573,6 +586,37 @@ func (n *Notifier) soundForDunst(req NotifyRequest) string {
 	return sound
 }
 
+// shareMode returns the share notify mode (mute|downgrade) while screen sharing, or "".
+//
+// Mute leaves dunst paused so notifications queue silently until share ends.
+func (n *Notifier) shareMode() string {
+	if n.state == nil || !n.state.GetScreenShare() {
+		return ""
+	}
+	return n.cfg.Share.WithDefaults().Notify
+}
+
+// redactSpec strips content from a notification shown during a screen share.
+func redactSpec(spec notificationSpec) notificationSpec {
+	low := "low"
+	spec.Title = shareRedactedTitle
+	spec.Body = ""
+	spec.Urgency = &low
+	spec.FocusAction = false
+	spec.Style = ""
+	return spec
+}
+
+// downgradeDunst silences third-party notifications while sharing and, in downgrade mode,
+// replaces them with a redacted copy so the content never stays on screen.
+func (n *Notifier) downgradeDunst(req NotifyRequest, mode string) error {
+	if mode != "downgrade" || fromHyprd(req) {
+		return nil
+	}
+	closeDunstNotification(req.NotificationID)
+	return n.sendDunst(redactSpec(notificationSpec{App: req.App, NoReplace: true}), nil)
+}
+
 func (n *Notifier) style(name string) config.ResolvedStyle {
 	if name == "" {
 		return config.ResolvedStyle{}
@@ -7,10 +7,13 @@ import (
 	"net"
 	"os"
 	"os/exec"
+	"path/filepath"
+	"slices"
 	"strings"
 	"time"
 
 	"dotfiles/cmds/internal/config"
+	"dotfiles/cmds/internal/hyprd/state"
 )
 
 const (
@@ -21,9 +24,12 @@ const (
 
 var errNoActiveMonitors = errors.New("no active hyprland monitors")
 
-// BG manages a single mpvpaper wallpaper process.
+// BG manages a single mpvpaper wallpaper process and its playlist selection.
+//
+// The selection lives in state so pins survive rebuilds; switching files and visual
+// values goes through mpv's IPC socket instead of respawning mpvpaper.
 type BG struct {
-	cfg *config.BackgroundConfig
+	state *state.State
 }
 
 const (
@@ -31,23 +37,164 @@ const (
 	backgroundOOMScoreAdj = "1000"
 )
 
-func NewBG(cfg *config.BackgroundConfig) *BG {
-	return &BG{cfg: cfg}
+func NewBG(s *state.State) *BG {
+	return &BG{state: s}
 }
 
-// Execute runs "ensure" (spawn if dead) or "kill" (pkill all).
-func (b *BG) Execute(mode string) (string, error) {
+// Execute runs "ensure" (spawn if dead), "kill" (pkill all), playlist next/prev/set, or status.
+func (b *BG) Execute(arg string) (string, error) {
+	mode, value, _ := strings.Cut(strings.TrimSpace(arg), " ")
 	switch mode {
 	case "ensure":
 		return b.ensure(bgStartTimeout)
 	case "kill":
 		b.killAll()
 		return "bg: killed", nil
+	case "next":
+		return b.step(1)
+	case "prev":
+		return b.step(-1)
+	case "set":
+		return b.set(strings.TrimSpace(value))
+	case "status":
+		return b.status(), nil
 	default:
-		return "", fmt.Errorf("unknown bg mode: %s (ensure|kill)", mode)
+		return "", fmt.Errorf("unknown bg mode: %s (ensure|kill|next|prev|set <file>|status)", mode)
 	}
 }
 
+func (b *BG) cfg() config.BackgroundConfig {
+	return b.state.GetConfig().Background
+}
+
+func (b *BG) session() string {
+	return b.state.GetActiveSession(b.state.GetWorkspace())
+}
+
+// current returns the wallpaper to show: the stored selection while a playlist or pin
+// applies, else the schedule's pick (the static wallpaper when there is no playlist).
+func (b *BG) current() config.Wallpaper {
+	cfg := b.cfg()
+	if sel := b.state.GetBackground(); sel != nil && sel.File != "" && (sel.Pinned || len(cfg.Playlist) > 0) {
+		return resolveWallpaper(cfg, sel.File)
+	}
+	return cfg.Scheduled(time.Now(), b.session())
+}
+
+// resolveWallpaper applies the first playlist entry for file, or the base wallpaper's values.
+func resolveWallpaper(cfg config.BackgroundConfig, file string) config.Wallpaper {
+	for _, entry := range cfg.Playlist {
+		if entry.File == file {
+			return entry.Resolve(cfg.Wallpaper)
+		}
+	}
+	w := cfg.Wallpaper
+	w.File = file
+	return w
+}
+
+// Apply switches to the schedule's pick unless a pin still covers it. The daemon calls it every minute.
+func (b *BG) Apply() error {
+	cfg := b.cfg()
+	if len(cfg.Playlist) == 0 {
+		return nil
+	}
+	pick := cfg.Scheduled(time.Now(), b.session())
+	if sel := b.state.GetBackground(); sel != nil {
+		if sel.Pinned && sel.Scheduled == pick.File || !sel.Pinned && sel.File == pick.File {
+			return nil
+		}
+	}
+	b.state.SetBackground(&state.BackgroundState{File: pick.File})
+	return b.load(pick)
+}
+
+// step pins the playlist entry delta positions from the current wallpaper.
+func (b *BG) step(delta int) (string, error) {
+	cfg := b.cfg()
+	if len(cfg.Playlist) == 0 {
+		return "", errors.New("bg: background.playlist is empty")
+	}
+	cur := b.current().File
+	idx := slices.IndexFunc(cfg.Playlist, func(e config.PlaylistEntry) bool { return e.File == cur })
+	if idx < 0 {
+		// Off-playlist: next starts at the first entry, prev at the last.
+		idx = 0
+		if delta > 0 {
+			idx = -1
+		}
+	}
+	idx = wrapIndex(idx+delta, len(cfg.Playlist))
+	return b.pin(cfg, cfg.Playlist[idx].Resolve(cfg.Wallpaper))
+}
+
+// set pins file (relative to video_path), which need not be in the playlist.
+func (b *BG) set(file string) (string, error) {
+	if file == "" {
+		return "", errors.New("usage: bg set <file>")
+	}
+	cfg := b.cfg()
+	if _, err := os.Stat(filepath.Join(config.ExpandPath(cfg.VideoPath), file)); err != nil {
+		return "", fmt.Errorf("bg: %w", err)
+	}
+	return b.pin(cfg, resolveWallpaper(cfg, file))
+}
+
+func (b *BG) pin(cfg config.BackgroundConfig, w config.Wallpaper) (string, error) {
+	pick := cfg.Scheduled(time.Now(), b.session())
+	b.state.SetBackground(&state.BackgroundState{File: w.File, Pinned: true, Scheduled: pick.File})
+	if err := b.load(w); err != nil {
+		return "", err
+	}
+	return "bg: " + w.File + " (pinned)", nil
+}
+
+// load switches a running mpvpaper to w over IPC; a dead one picks w up on its next spawn.
+func (b *BG) load(w config.Wallpaper) error {
+	if !b.isAlive() {
+		return nil
+	}
+	cfg := b.cfg()
+	path := filepath.Join(config.ExpandPath(cfg.VideoPath), w.File)
+	if err := mpvCommand(cfg.Socket, "loadfile", path, "replace"); err != nil {
+		return fmt.Errorf("bg: load %s: %w", w.File, err)
+	}
+	for _, prop := range []struct {
+		name  string
+		value int
+	}{
+		{"brightness", w.Brightness},
+		{"contrast", w.Contrast},
+		{"saturation", w.Saturation},
+		{"hue", w.Hue},
+	} {
+		if err := mpvCommand(cfg.Socket, "set_property", prop.name, prop.value); err != nil {
+			return fmt.Errorf("bg: set %s: %w", prop.name, err)
+		}
+	}
+	return nil
+}
+
+func (b *BG) status() string {
+	cfg := b.cfg()
+	w := b.current()
+	var notes []string
+	switch sel := b.state.GetBackground(); {
+	case sel != nil && sel.Pinned:
+		notes = append(notes, "pinned until the schedule changes")
+	case len(cfg.Playlist) > 0:
+		notes = append(notes, "scheduled")
+	}
+	if !b.isAlive() {
+		notes = append(notes, "not running")
+	}
+	line := "bg: " + w.File
+	if len(notes) > 0 {
+		line += " (" + strings.Join(notes, ", ") + ")"
+	}
+	return line
+}
+
 func (b *BG) ensure(timeout time.Duration) (string, error) {
 	if b.isAlive() {
 		return "bg: running", nil
@@ -68,7 +215,7 @@ func (b *BG) ensure(timeout time.Duration) (string, error) {
 }
 
 func (b *BG) isAlive() bool {
-	conn, err := net.DialTimeout("unix", b.cfg.Socket, 200*time.Millisecond)
+	conn, err := net.DialTimeout("unix", b.cfg().Socket, 200*time.Millisecond)
 	if err != nil {
 		return false
 	}
@@ -88,15 +235,16 @@ func (b *BG) waitAlive(timeout time.Duration) bool {
 }
 
 func (b *BG) spawn() (string, error) {
-	v := &b.cfg.Wallpaper
-	display, err := b.resolveDisplay()
+	cfg := b.cfg()
+	v := b.current()
+	display, err := b.resolveDisplay(cfg)
 	if err != nil {
 		return "", err
 	}
-	videoPath := config.ExpandPath(b.cfg.VideoPath)
+	videoPath := config.ExpandPath(cfg.VideoPath)
 	fullPath := videoPath + "/" + v.File
 	opts := fmt.Sprintf("--loop --input-ipc-server=%s --brightness=%d --contrast=%d --saturation=%d --hue=%d",
-		b.cfg.Socket, v.Brightness, v.Contrast, v.Saturation, v.Hue)
+		cfg.Socket, v.Brightness, v.Contrast, v.Saturation, v.Hue)
 	cmd := exec.Command("taskset", "-c", backgroundCPUs, "mpvpaper", "-p", "-o", opts, display, fullPath)
 	if err := cmd.Start(); err != nil {
 		return "", fmt.Errorf("start mpvpaper: %w", err)
@@ -114,8 +262,8 @@ func (b *BG) spawn() (string, error) {
 	return display, nil
 }
 
-func (b *BG) resolveDisplay() (string, error) {
-	display := strings.TrimSpace(b.cfg.Display)
+func (b *BG) resolveDisplay(cfg config.BackgroundConfig) (string, error) {
+	display := strings.TrimSpace(cfg.Display)
 	if display != "" && display != "auto" {
 		return display, nil
 	}
@@ -154,15 +302,15 @@ func (b *BG) killAll() {
 }
 
 // EnsureBG spawns the wallpaper if not already running.
-func EnsureBG(cfg *config.BackgroundConfig) error {
-	bg := NewBG(cfg)
+func EnsureBG(s *state.State) error {
+	bg := NewBG(s)
 	_, err := bg.Execute("ensure")
 	return err
 }
 
 // EnsureBGBoot waits long enough for cold-boot display and media startup before locking.
-func EnsureBGBoot(cfg *config.BackgroundConfig) error {
-	bg := NewBG(cfg)
+func EnsureBGBoot(s *state.State) error {
+	bg := NewBG(s)
 	_, err := bg.ensure(bgBootStartTimeout)
 	return err
 }
@@ -0,0 +1,243 @@
+package session
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Boot step states reported in BootStepStatus.State.
+const (
+	bootPending = "pending"
+	bootRunning = "running"
+	bootDone    = "done"
+	bootFailed  = "failed"
+	bootSkipped = "skipped"
+)
+
+var errBootTimeout = errors.New("timed out")
+
+// bootStep is one node in the boot DAG.
+//
+// Steps start as soon as every dep has finished; a failed critical step skips its dependents,
+// while other failures only delay them. Timeout 0 means unbounded (e.g. waiting on hyprlock).
+type bootStep struct {
+	name     string
+	deps     []string
+	timeout  time.Duration
+	retries  int // extra attempts after an error; timeouts are not retried
+	critical bool
+	run      func() error
+}
+
+// BootStepStatus is the published progress of one boot step.
+type BootStepStatus struct {
+	Name     string    `json:"name"`
+	Deps     []string  `json:"deps,omitempty"`
+	State    string    `json:"state"`
+	Attempt  int       `json:"attempt,omitempty"`
+	Started  time.Time `json:"started,omitzero"`
+	Finished time.Time `json:"finished,omitzero"`
+	Error    string    `json:"error,omitempty"`
+}
+
+// BootTimeline is the full boot progress snapshot published on the `init` topic.
+type BootTimeline struct {
+	Profile  string           `json:"profile,omitempty"` // boot profile in effect, if any
+	Started  time.Time        `json:"started"`
+	Finished time.Time        `json:"finished,omitzero"`
+	Steps    []BootStepStatus `json:"steps"`
+}
+
+// Failed returns the names of failed steps in declaration order.
+func (t BootTimeline) Failed() []string {
+	var failed []string
+	for _, step := range t.Steps {
+		if step.State == bootFailed {
+			failed = append(failed, step.Name)
+		}
+	}
+	return failed
+}
+
+// String renders the timeline for `hyprd init --status`.
+func (t BootTimeline) String() string {
+	if t.Started.IsZero() {
+		return "no boot recorded"
+	}
+
+	status := "running"
+	elapsed := time.Since(t.Started)
+	if !t.Finished.IsZero() {
+		status = "complete"
+		if failed := t.Failed(); len(failed) > 0 {
+			status = fmt.Sprintf("%d failed", len(failed))
+		}
+		elapsed = t.Finished.Sub(t.Started)
+	}
+
+	header := fmt.Sprintf("boot %s (%s, %s)", t.Started.Format("2006-01-02 15:04:05"), elapsed.Round(100*time.Millisecond), status)
+	if t.Profile != "" {
+		header += " profile " + t.Profile
+	}
+	lines := []string{header}
+	for _, step := range t.Steps {
+		line := fmt.Sprintf("  %-8s %-24s", step.State, step.Name)
+		if !step.Started.IsZero() {
+			end := step.Finished
+			if end.IsZero() {
+				end = time.Now()
+			}
+			line += fmt.Sprintf(" +%-6s %s", step.Started.Sub(t.Started).Round(100*time.Millisecond), end.Sub(step.Started).Round(100*time.Millisecond))
+		}
+		line = strings.TrimRight(line, " ")
+		if step.Attempt > 1 {
+			line += fmt.Sprintf(" (attempt %d)", step.Attempt)
+		}
+		if step.Error != "" {
+			line += ": " + step.Error
+		}
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// bootRunner executes a step DAG and publishes a timeline copy after every state change.
+type bootRunner struct {
+	mu       sync.Mutex
+	timeline BootTimeline
+	index    map[string]int
+	publish  func(BootTimeline)
+}
+
+// runBoot executes steps respecting deps and returns the final timeline.
+//
+// Unknown deps are treated as already satisfied so optional steps can be left out of the graph.
+func runBoot(profile string, steps []bootStep, publish func(BootTimeline)) BootTimeline {
+	r := &bootRunner{
+		timeline: BootTimeline{Profile: profile, Started: time.Now()},
+		index:    make(map[string]int, len(steps)),
+		publish:  publish,
+	}
+	done := make(map[string]chan struct{}, len(steps))
+	for i, step := range steps {
+		r.index[step.name] = i
+		r.timeline.Steps = append(r.timeline.Steps, BootStepStatus{Name: step.name, Deps: step.deps, State: bootPending})
+		done[step.name] = make(chan struct{})
+	}
+	r.emit()
+
+	var wg sync.WaitGroup
+	for _, step := range steps {
+		wg.Go(func() {
+			defer close(done[step.name])
+			for _, dep := range step.deps {
+				if ch, ok := done[dep]; ok {
+					<-ch
+				}
+			}
+			if blocker := r.criticalFailure(step.deps, steps); blocker != "" {
+				r.update(step.name, func(s *BootStepStatus) {
+					s.State = bootSkipped
+					s.Error = blocker + " failed"
+				})
+				return
+			}
+			r.execute(step)
+		})
+	}
+	wg.Wait()
+
+	r.mu.Lock()
+	r.timeline.Finished = time.Now()
+	r.mu.Unlock()
+	r.emit()
+	return r.snapshot()
+}
+
+func (r *bootRunner) execute(step bootStep) {
+	r.update(step.name, func(s *BootStepStatus) {
+		s.State = bootRunning
+		s.Started = time.Now()
+	})
+
+	var err error
+	for attempt := 1; attempt <= step.retries+1; attempt++ {
+		if attempt > 1 {
+			r.update(step.name, func(s *BootStepStatus) { s.Attempt = attempt; s.Error = err.Error() })
+		}
+		err = runWithTimeout(step.run, step.timeout)
+		if err == nil || errors.Is(err, errBootTimeout) {
+			break
+		}
+	}
+
+	r.update(step.name, func(s *BootStepStatus) {
+		s.Finished = time.Now()
+		s.State = bootDone
+		s.Error = ""
+		if err != nil {
+			s.State = bootFailed
+			s.Error = err.Error()
+		}
+	})
+}
+
+// runWithTimeout bounds fn by timeout. A timed-out fn keeps running in the background because
+// the Hyprland and process calls it makes are not cancellable; dependents simply stop waiting.
+func runWithTimeout(fn func() error, timeout time.Duration) error {
+	if timeout <= 0 {
+		return fn()
+	}
+	result := make(chan error, 1)
+	go func() { result <- fn() }()
+	select {
+	case err := <-result:
+		return err
+	case <-time.After(timeout):
+		return fmt.Errorf("%w after %s", errBootTimeout, timeout)
+	}
+}
+
+// criticalFailure returns the first dep that was skipped or failed critically, or "".
+//
+// Skips propagate so a failed critical root blocks its whole subtree.
+func (r *bootRunner) criticalFailure(deps []string, steps []bootStep) string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, dep := range deps {
+		i, ok := r.index[dep]
+		if !ok {
+			continue
+		}
+		state := r.timeline.Steps[i].State
+		if state == bootSkipped || state == bootFailed && steps[i].critical {
+			return dep
+		}
+	}
+	return ""
+}
+
+func (r *bootRunner) update(name string, fn func(*BootStepStatus)) {
+	r.mu.Lock()
+	fn(&r.timeline.Steps[r.index[name]])
+	r.mu.Unlock()
+	r.emit()
+}
+
+func (r *bootRunner) snapshot() BootTimeline {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	copied := r.timeline
+	copied.Steps = slices.Clone(r.timeline.Steps)
+	return copied
+}
+
+func (r *bootRunner) emit() {
+	if r.publish != nil {
+		r.publish(r.snapshot())
+	}
+}
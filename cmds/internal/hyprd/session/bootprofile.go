@@ -0,0 +1,95 @@
+package session
+
+import (
+	"cmp"
+	"fmt"
+	"os"
+	"os/exec"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"dotfiles/cmds/internal/config"
+)
+
+// bootPlan is the init configuration after applying the selected boot profile.
+type bootPlan struct {
+	profile   string // "" when no profile matched
+	sessions  []config.Session
+	workspace int
+	lock      bool
+	bluetooth config.BluetoothConfig
+	vpn       []string
+	wallpaper string
+}
+
+// plan resolves the boot profile: the --profile override when set, else the first whose
+// rules match the current host, time, Wi-Fi network, and monitors.
+func (i *Init) plan(cfg *config.HyprConfig) (bootPlan, error) {
+	var profile config.BootProfile
+	switch {
+	case i.profile != "":
+		p, ok := cfg.Init.Profile(i.profile)
+		if !ok {
+			return bootPlan{}, fmt.Errorf("unknown boot profile: %s", i.profile)
+		}
+		profile = p
+	case len(cfg.Init.Profiles) > 0:
+		profile, _ = cfg.Init.SelectProfile(i.bootContext())
+	}
+
+	p := bootPlan{
+		profile:   profile.Name,
+		workspace: cmp.Or(profile.Workspace, cfg.Init.Workspace),
+		lock:      cfg.Init.Lock,
+		bluetooth: profile.BluetoothFor(cfg.Bluetooth),
+		vpn:       profile.VPN,
+		wallpaper: profile.Wallpaper,
+	}
+	if profile.Lock != nil {
+		p.lock = *profile.Lock
+	}
+	for _, s := range cfg.Sessions {
+		if profile.Sessions != nil && slices.Contains(profile.Sessions, s.Name) || profile.Sessions == nil && s.Init {
+			p.sessions = append(p.sessions, s)
+		}
+	}
+	sort.Slice(p.sessions, func(a, b int) bool {
+		return p.sessions[a].Workspace < p.sessions[b].Workspace
+	})
+	return p, nil
+}
+
+// bootContext gathers what profile rules match on; lookups that fail leave their field empty.
+func (i *Init) bootContext() config.BootContext {
+	ctx := config.BootContext{Now: time.Now(), SSID: connectedSSID()}
+	ctx.Hostname, _ = os.Hostname()
+	if monitors, err := i.hypr.Monitors(); err == nil {
+		for _, m := range monitors {
+			ctx.Monitors = append(ctx.Monitors, m.Name, m.Description)
+		}
+	}
+	return ctx
+}
+
+// connectedSSID returns the active Wi-Fi network from NetworkManager.
+func connectedSSID() string {
+	out, err := exec.Command("nmcli", "-t", "-f", "ACTIVE,SSID", "device", "wifi").Output()
+	if err != nil {
+		return ""
+	}
+	for line := range strings.Lines(string(out)) {
+		if ssid, ok := strings.CutPrefix(strings.TrimSpace(line), "yes:"); ok {
+			return strings.ReplaceAll(ssid, `\:`, ":")
+		}
+	}
+	return ""
+}
+
+// profileBluetooth returns the bluetooth config for the boot profile recorded in state, so
+// unlock reconnects the same device boot did.
+func profileBluetooth(cfg *config.HyprConfig, name string) config.BluetoothConfig {
+	profile, _ := cfg.Init.Profile(name)
+	return profile.BluetoothFor(cfg.Bluetooth)
+}
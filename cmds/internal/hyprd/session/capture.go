@@ -0,0 +1,366 @@
+package session
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"dotfiles/cmds/internal/config"
+	"dotfiles/cmds/internal/hyprd/browser"
+	"dotfiles/cmds/internal/hyprd/hypr"
+	"dotfiles/cmds/internal/hyprd/kitty"
+	"dotfiles/cmds/internal/hyprd/windows"
+
+	"gopkg.in/yaml.v3"
+)
+
+const layoutSaveUsage = "usage: layout save <name> [--workspace N] [--append]"
+
+// capturedSession mirrors config.Session's YAML shape with empty fields omitted.
+//
+// Body is emitted in master/slave/shadow order, so no explicit layout block is needed.
+type capturedSession struct {
+	Project string            `yaml:"project,omitempty"`
+	Body    []string          `yaml:"body,omitempty"`
+	Browser string            `yaml:"browser,omitempty"`
+	Tabs    map[string]string `yaml:"tabs,omitempty"`
+	Command string            `yaml:"command,omitempty"`
+	Class   string            `yaml:"class,omitempty"`
+	Monocle bool              `yaml:"monocle,omitempty"`
+}
+
+// save captures a live workspace as a session block: the inverse of openSession.
+//
+// By default the block is written to a drop-in fragment for review; --append inserts it
+// into the workspace's group in hyprd.yaml after checking the edited catalog still parses.
+func (l *Layout) save(args []string) (string, error) {
+	var name string
+	ws := 0
+	appendCatalog := false
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--workspace", "-w":
+			if i+1 >= len(args) {
+				return "", errors.New(layoutSaveUsage)
+			}
+			n, err := strconv.Atoi(args[i+1])
+			if err != nil || n <= 0 {
+				return "", fmt.Errorf("invalid workspace: %s", args[i+1])
+			}
+			ws = n
+			i++
+		case "--append":
+			appendCatalog = true
+		default:
+			if name != "" || strings.HasPrefix(args[i], "-") {
+				return "", errors.New(layoutSaveUsage)
+			}
+			name = args[i]
+		}
+	}
+	if name == "" {
+		return "", errors.New(layoutSaveUsage)
+	}
+	if slices.Contains(config.LayoutVerbs, name) {
+		return "", fmt.Errorf("session name %q is a layout subcommand", name)
+	}
+	if _, exists := l.state.GetConfig().Sessions[name]; exists {
+		return "", fmt.Errorf("session %q already exists", name)
+	}
+	if ws == 0 {
+		active, err := l.hypr.ActiveWorkspace()
+		if err != nil {
+			return "", err
+		}
+		ws = active
+	}
+
+	captured, err := l.capture(name, ws)
+	if err != nil {
+		return "", err
+	}
+	block, err := marshalSessionBlock(ws, name, captured)
+	if err != nil {
+		return "", err
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	if appendCatalog {
+		path := filepath.Join(home, config.ConfigPath("hyprd"))
+		if err := appendSessionToCatalog(path, ws, name, captured); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("saved session: %s on ws%d -> %s\n%s", name, ws, path, block), nil
+	}
+
+	path := filepath.Join(home, config.DropInPath("hyprd", name))
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return "", err
+	}
+	header := fmt.Sprintf("# captured by `hyprd layout save %s`; review, then merge into hyprd.yaml sessions.\n", name)
+	if err := os.WriteFile(path, []byte(header+"sessions:\n"+indentBlock(block, "  ")), 0o644); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("saved session: %s on ws%d -> %s\n%s", name, ws, path, block), nil
+}
+
+// capture reads roles, three-body order, tab profiles, project, and browser state from workspace ws.
+func (l *Layout) capture(name string, ws int) (capturedSession, error) {
+	ordered, err := l.captureWindows(ws)
+	if err != nil {
+		return capturedSession{}, err
+	}
+	if len(ordered) == 0 {
+		return capturedSession{}, fmt.Errorf("ws%d has no windows to capture", ws)
+	}
+
+	cfg := l.state.GetConfig()
+	probe := config.Session{Name: name, Workspace: ws}
+	var captured capturedSession
+	var browserWindow *hypr.Window
+	for i := range ordered {
+		w := ordered[i]
+		role := l.captureRole(probe, &w)
+		if role == "" || slices.Contains(captured.Body, role) {
+			continue
+		}
+		captured.Body = append(captured.Body, role)
+		if role == "browser" && browserWindow == nil {
+			browserWindow = &w
+		}
+		if role != "browser" && strings.EqualFold(w.Class, "kitty") {
+			profile, err := kitty.DetectProfile(cfg, w.Pid)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "layout save: %s tab profile: %v\n", role, err)
+				continue
+			}
+			if profile != role {
+				if captured.Tabs == nil {
+					captured.Tabs = make(map[string]string)
+				}
+				captured.Tabs[role] = profile
+			}
+		}
+	}
+
+	// Body sessions need at least a master/slave pair; otherwise fall back to a single-command session.
+	if len(captured.Body) < 2 {
+		idx := slices.IndexFunc(ordered, func(w hypr.Window) bool {
+			return browserWindow == nil || w.Address != browserWindow.Address
+		})
+		if idx < 0 {
+			return capturedSession{}, fmt.Errorf("ws%d: cannot derive a body or command from its windows", ws)
+		}
+		primary := ordered[idx]
+		captured.Body = nil
+		captured.Tabs = nil
+		captured.Command = processCommand(primary.Pid)
+		if captured.Command == "" {
+			return capturedSession{}, fmt.Errorf("ws%d: read command for %s", ws, primary.Class)
+		}
+		if !strings.EqualFold(commandName(captured.Command), primary.Class) {
+			captured.Class = primary.Class
+		}
+	}
+
+	if path := l.state.GetProjectPath(ws); path != "" {
+		home, _ := os.UserHomeDir()
+		if rel, err := filepath.Rel(home, path); err == nil && !strings.HasPrefix(rel, "..") {
+			captured.Project = rel
+		}
+	}
+	captured.Monocle = l.state.GetMonocle(ws) != nil
+
+	if browserWindow != nil {
+		dir, err := browser.NewBrowser(l.hypr, l.state).SnapshotWindow(name, *browserWindow)
+		if err != nil {
+			return capturedSession{}, fmt.Errorf("snapshot browser: %w", err)
+		}
+		captured.Browser = filepath.Base(dir)
+	}
+	return captured, nil
+}
+
+// captureWindows returns ws windows in layout order: three-body master/active/shadow, else tiled then floating.
+//
+// Windows parked by monocle and the three-body shadow live on special workspaces and are pulled back in.
+func (l *Layout) captureWindows(ws int) ([]hypr.Window, error) {
+	clients, err := l.hypr.Clients()
+	if err != nil {
+		return nil, err
+	}
+	byAddr := make(map[string]hypr.Window, len(clients))
+	for _, c := range clients {
+		byAddr[c.Address] = c
+	}
+
+	var ordered []hypr.Window
+	seen := make(map[string]bool)
+	add := func(addr string) {
+		c, ok := byAddr[addr]
+		if !ok || seen[addr] || c.Pinned || windows.IsIgnored(c.Class) {
+			return
+		}
+		seen[addr] = true
+		ordered = append(ordered, c)
+	}
+
+	if tb := l.state.GetThreeBody(ws); tb != nil {
+		add(tb.Master)
+		add(tb.Active)
+		add(tb.Shadow)
+	}
+	if ms := l.state.GetMonocle(ws); ms != nil {
+		add(ms.Master)
+		add(ms.Focused)
+		for _, mw := range ms.Windows {
+			add(mw.Address)
+		}
+	}
+	tiled, err := windows.GetTiledWindows(l.hypr, ws)
+	if err != nil {
+		return nil, err
+	}
+	for _, w := range tiled {
+		add(w.Address)
+	}
+	for _, c := range clients {
+		if c.Workspace.ID == ws {
+			add(c.Address)
+		}
+	}
+	return ordered, nil
+}
+
+// captureRole maps a live window back to its three-body role, or "" for windows outside the catalog.
+func (l *Layout) captureRole(probe config.Session, w *hypr.Window) string {
+	for _, role := range []string{"editor", "agents", "browser"} {
+		if l.matchesRole(probe, w, role) {
+			return role
+		}
+	}
+	return ""
+}
+
+// processCommand reconstructs a launch command from /proc/<pid>/cmdline, quoting args with spaces.
+func processCommand(pid int) string {
+	if pid <= 0 {
+		return ""
+	}
+	data, err := os.ReadFile(fmt.Sprintf("/proc/%d/cmdline", pid))
+	if err != nil {
+		return ""
+	}
+	var parts []string
+	for _, arg := range strings.Split(strings.TrimRight(string(data), "\x00"), "\x00") {
+		if strings.ContainsAny(arg, " '\"") {
+			arg = "'" + strings.ReplaceAll(arg, "'", `'\''`) + "'"
+		}
+		parts = append(parts, arg)
+	}
+	return strings.Join(parts, " ")
+}
+
+// marshalSessionBlock renders `<ws>:\n  <name>:\n    ...` at the indentation used under `sessions:`.
+func marshalSessionBlock(ws int, name string, s any) (string, error) {
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	doc := map[int]map[string]any{ws: {name: s}}
+	if err := enc.Encode(doc); err != nil {
+		return "", err
+	}
+	if err := enc.Close(); err != nil {
+		return "", err
+	}
+	return buf.String(), nil
+}
+
+func indentBlock(block, prefix string) string {
+	lines := strings.SplitAfter(block, "\n")
+	for i, line := range lines {
+		if strings.TrimSpace(line) != "" {
+			lines[i] = prefix + line
+		}
+	}
+	return strings.Join(lines, "")
+}
+
+var catalogTopKey = regexp.MustCompile(`^[A-Za-z_]+:`)
+
+// appendSessionToCatalog inserts the session at the end of its workspace group in hyprd.yaml.
+//
+// Text insertion keeps the catalog's comments and layout; a missing group is appended to the
+// sessions section. The edited file must still decode before it replaces the original.
+func appendSessionToCatalog(path string, ws int, name string, s capturedSession) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+
+	session, err := marshalSessionBlock(ws, name, s)
+	if err != nil {
+		return err
+	}
+	// Drop the "<ws>:" line; the session itself sits four spaces under sessions:.
+	_, sessionBody, _ := strings.Cut(session, "\n")
+	sessionBody = indentBlock(sessionBody, "  ")
+
+	lines := strings.SplitAfter(string(data), "\n")
+	start := slices.IndexFunc(lines, func(line string) bool { return strings.HasPrefix(line, "sessions:") })
+	if start < 0 {
+		return fmt.Errorf("%s: no sessions section", path)
+	}
+	end := len(lines)
+	for i := start + 1; i < len(lines); i++ {
+		if catalogTopKey.MatchString(lines[i]) {
+			end = i
+			break
+		}
+	}
+
+	groupKey := regexp.MustCompile(fmt.Sprintf(`^  %d:(\s|$)`, ws))
+	groupEnd := -1
+	for i := start + 1; i < end; i++ {
+		if !groupKey.MatchString(lines[i]) {
+			continue
+		}
+		groupEnd = end
+		for j := i + 1; j < end; j++ {
+			if strings.HasPrefix(lines[j], "  ") && !strings.HasPrefix(lines[j], "   ") {
+				groupEnd = j
+				break
+			}
+		}
+		break
+	}
+
+	insert := "\n" + sessionBody
+	if groupEnd < 0 {
+		groupEnd = end
+		insert = fmt.Sprintf("\n  %d:\n", ws) + sessionBody
+	}
+	// Insert after the group's last content line so trailing blank lines stay between groups.
+	for groupEnd > start+1 && strings.TrimSpace(lines[groupEnd-1]) == "" {
+		groupEnd--
+	}
+
+	edited := strings.Join(lines[:groupEnd], "") + insert + strings.Join(lines[groupEnd:], "")
+	var check config.HyprConfig
+	if err := yaml.Unmarshal([]byte(edited), &check); err != nil {
+		return fmt.Errorf("edited catalog no longer parses: %w", err)
+	}
+	if _, ok := check.Sessions[name]; !ok {
+		return fmt.Errorf("edited catalog is missing session %q", name)
+	}
+	return os.WriteFile(path, []byte(edited), 0o644)
+}
@@ -0,0 +1,48 @@
+package session
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+
+	"dotfiles/cmds/internal/config"
+)
+
+// bgFrameOffset skips fade-ins when ffmpeg grabs a frame from the video file.
+const bgFrameOffset = "3"
+
+// WallpaperPath returns the absolute path of the wallpaper currently selected.
+func (b *BG) WallpaperPath() string {
+	return filepath.Join(config.ExpandPath(b.cfg().VideoPath), b.current().File)
+}
+
+// Frame writes a PNG of the wallpaper to dest: the frame mpvpaper is showing, via mpv's
+// screenshot-to-file, or an ffmpeg grab of the file when mpvpaper is not running.
+func (b *BG) Frame(dest string) error {
+	if b.isAlive() && mpvCommand(b.cfg().Socket, "screenshot-to-file", dest, "video") == nil {
+		return nil
+	}
+	path := b.WallpaperPath()
+	err := ffmpegFrame(path, dest, "-ss", bgFrameOffset)
+	if err != nil {
+		// Clips shorter than the offset produce no frame; take the first one instead.
+		err = ffmpegFrame(path, dest)
+	}
+	if err != nil {
+		return fmt.Errorf("bg: frame of %s: %w", filepath.Base(path), err)
+	}
+	return nil
+}
+
+func ffmpegFrame(src, dest string, seek ...string) error {
+	args := append([]string{"-v", "error", "-y"}, seek...)
+	args = append(args, "-i", src, "-frames:v", "1", dest)
+	if out, err := exec.Command("ffmpeg", args...).CombinedOutput(); err != nil {
+		return fmt.Errorf("%v: %s", err, out)
+	}
+	if st, err := os.Stat(dest); err != nil || st.Size() == 0 {
+		return fmt.Errorf("ffmpeg wrote no frame")
+	}
+	return nil
+}
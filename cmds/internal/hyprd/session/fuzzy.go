@@ -0,0 +1,139 @@
+package session
+
+import (
+	"cmp"
+	"dotfiles/cmds/internal/config"
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+// Field weights: a name hit outranks the same hit in a project path or body role.
+const (
+	fuzzyWeightName    = 3
+	fuzzyWeightProject = 2
+	fuzzyWeightRole    = 1
+)
+
+// pickerMatch is one ranked session for a picker filter query.
+type pickerMatch struct {
+	Name    string
+	WS      int
+	Score   int
+	Matched []string // fields that matched: name, project, role
+}
+
+// rankSessions scores every session against query and returns matches best-first.
+//
+// Each whitespace-separated term must match at least one of the session's name, project,
+// or body roles; a session's score is the sum of each term's best weighted field score.
+// Ties prefer the current workspace, then workspace order, then name.
+func rankSessions(sessions map[string]config.Session, query string, currentWS int) []pickerMatch {
+	terms := strings.Fields(strings.ToLower(query))
+	if len(terms) == 0 {
+		return nil
+	}
+
+	var matches []pickerMatch
+	for name, s := range sessions {
+		if s.Workspace == 1 {
+			continue
+		}
+		if m, ok := matchSession(name, s, terms); ok {
+			matches = append(matches, m)
+		}
+	}
+
+	slices.SortFunc(matches, func(a, b pickerMatch) int {
+		if c := cmp.Compare(b.Score, a.Score); c != 0 {
+			return c
+		}
+		if (a.WS == currentWS) != (b.WS == currentWS) {
+			if a.WS == currentWS {
+				return -1
+			}
+			return 1
+		}
+		return cmp.Or(cmp.Compare(a.WS, b.WS), cmp.Compare(a.Name, b.Name))
+	})
+	return matches
+}
+
+func matchSession(name string, s config.Session, terms []string) (pickerMatch, bool) {
+	type field struct {
+		kind   string
+		text   string
+		weight int
+	}
+	fields := []field{{"name", name, fuzzyWeightName}}
+	if s.Project != "" {
+		fields = append(fields, field{"project", s.Project, fuzzyWeightProject})
+	}
+	for _, role := range s.Body {
+		fields = append(fields, field{"role", role, fuzzyWeightRole})
+	}
+
+	m := pickerMatch{Name: name, WS: s.Workspace}
+	for _, term := range terms {
+		best, kind := 0, ""
+		for _, f := range fields {
+			if score, ok := fuzzyScore(term, f.text); ok && score*f.weight > best {
+				best, kind = score*f.weight, f.kind
+			}
+		}
+		if best == 0 {
+			return pickerMatch{}, false
+		}
+		m.Score += best
+		if !slices.Contains(m.Matched, kind) {
+			m.Matched = append(m.Matched, kind)
+		}
+	}
+	return m, true
+}
+
+// fuzzyScore scores pattern as a case-insensitive subsequence of text; ok is false when it isn't one.
+//
+// Consecutive runs and hits at word starts (after - _ . / or space) earn bonuses, a prefix
+// match earns more, and each skipped character costs a point so tighter matches rank first.
+func fuzzyScore(pattern, text string) (int, bool) {
+	if pattern == "" {
+		return 0, false
+	}
+	lower := strings.ToLower(text)
+	if strings.HasPrefix(lower, pattern) {
+		score := 10 + 5*utf8.RuneCountInString(pattern)
+		if len(lower) == len(pattern) {
+			score += 10
+		}
+		return score, true
+	}
+
+	score, run, gaps := 0, 0, 0
+	want := []rune(pattern)
+	prev := ' '
+	i := 0
+	for _, r := range lower {
+		if i < len(want) && r == want[i] {
+			score++
+			if run > 0 {
+				score += 2 * run
+			}
+			if strings.ContainsRune(" -_./", prev) {
+				score += 4
+			}
+			run++
+			i++
+		} else {
+			if i > 0 && i < len(want) {
+				gaps++
+			}
+			run = 0
+		}
+		prev = r
+	}
+	if i < len(want) {
+		return 0, false
+	}
+	return max(score-gaps, 1), true
+}
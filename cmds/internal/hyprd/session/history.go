@@ -0,0 +1,268 @@
+package session
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Presence values published on the `presence` topic.
+const (
+	PresenceActive = "active"
+	PresenceAway   = "away"
+	PresenceLocked = "locked"
+)
+
+// Lock kinds and the triggers that start or end them.
+const (
+	lockKindPseudo = "pseudo"
+	lockKindFull   = "full"
+
+	triggerManual = "manual"
+	triggerIdle   = "idle"
+	triggerBoot   = "boot"
+	triggerAuth   = "auth" // hyprlock exited after authentication
+)
+
+const lockHistoryUsage = "usage: lock history [--since <7d|24h|YYYY-MM-DD>]"
+
+// LockEvent is one lock transition, appended to the history log as a JSON line.
+//
+// Escalate marks a pseudo-lock upgraded to hyprlock; Unlock carries the strongest kind
+// reached and the whole period's duration, so reports only need unlock lines.
+type LockEvent struct {
+	Time    time.Time `json:"time"`
+	Event   string    `json:"event"` // lock|escalate|unlock
+	Kind    string    `json:"kind"`  // pseudo|full
+	Trigger string    `json:"trigger"`
+	Seconds int64     `json:"seconds,omitempty"`
+}
+
+// beginPeriod starts a lock period or escalates the active one. Called with l.mu held.
+func (l *Lock) beginPeriod(kind, trigger string) LockEvent {
+	now := time.Now()
+	event := LockEvent{Time: now, Event: "escalate", Kind: kind, Trigger: trigger}
+	if l.lockedAt.IsZero() {
+		event.Event = "lock"
+		l.lockedAt = now
+	}
+	l.kind = kind
+	return event
+}
+
+// endPeriod closes the active lock period. Called with l.mu held.
+func (l *Lock) endPeriod(trigger string) LockEvent {
+	now := time.Now()
+	event := LockEvent{Time: now, Event: "unlock", Kind: l.kind, Trigger: trigger}
+	if !l.lockedAt.IsZero() {
+		event.Seconds = int64(now.Sub(l.lockedAt).Seconds())
+	}
+	l.lockedAt = time.Time{}
+	l.kind = ""
+	return event
+}
+
+func (l *Lock) emit(event LockEvent) {
+	l.mu.Lock()
+	publish := l.publish
+	l.mu.Unlock()
+	if publish != nil {
+		publish(event)
+	}
+}
+
+// LockHistoryPath returns $XDG_STATE_HOME/hyprd/locks.jsonl (default ~/.local/state).
+func LockHistoryPath() (string, error) {
+	dir := os.Getenv("XDG_STATE_HOME")
+	if dir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		dir = filepath.Join(home, ".local", "state")
+	}
+	return filepath.Join(dir, "hyprd", "locks.jsonl"), nil
+}
+
+// AppendLockEvent appends event to the history log, creating it on first use.
+func AppendLockEvent(event LockEvent) error {
+	path, err := LockHistoryPath()
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = f.Write(append(data, '\n'))
+	return err
+}
+
+// readLockHistory returns logged events at or after since; malformed lines are skipped.
+func readLockHistory(since time.Time) ([]LockEvent, error) {
+	path, err := LockHistoryPath()
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var events []LockEvent
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var event LockEvent
+		if json.Unmarshal(scanner.Bytes(), &event) != nil || event.Time.Before(since) {
+			continue
+		}
+		event.Time = event.Time.Local()
+		events = append(events, event)
+	}
+	return events, scanner.Err()
+}
+
+// lockHistory renders `lock history`: per-day away time and lock counts since a cutoff (default 7 days).
+func lockHistory(args []string) (string, error) {
+	now := time.Now()
+	since := startOfDay(now).AddDate(0, 0, -6)
+	for i := 0; i < len(args); i++ {
+		if args[i] != "--since" || i+1 >= len(args) {
+			return "", errors.New(lockHistoryUsage)
+		}
+		parsed, err := parseSince(args[i+1], now)
+		if err != nil {
+			return "", err
+		}
+		since = parsed
+		i++
+	}
+
+	events, err := readLockHistory(since)
+	if err != nil {
+		return "", err
+	}
+	return lockReport(events, since, now), nil
+}
+
+// parseSince accepts a day count (7d), a Go duration (36h), or a date (YYYY-MM-DD).
+func parseSince(value string, now time.Time) (time.Time, error) {
+	if days, ok := strings.CutSuffix(value, "d"); ok {
+		if n, err := strconv.Atoi(days); err == nil && n > 0 {
+			return startOfDay(now).AddDate(0, 0, 1-n), nil
+		}
+	}
+	if d, err := time.ParseDuration(value); err == nil && d > 0 {
+		return now.Add(-d), nil
+	}
+	if t, err := time.ParseInLocation(time.DateOnly, value, now.Location()); err == nil {
+		return t, nil
+	}
+	return time.Time{}, fmt.Errorf("invalid --since %q: %s", value, lockHistoryUsage)
+}
+
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+type lockDay struct {
+	away   time.Duration
+	locks  int
+	full   int
+	byIdle int
+}
+
+// lockReport totals unlock periods per local day, splitting away time across midnight.
+func lockReport(events []LockEvent, since, now time.Time) string {
+	days := make(map[time.Time]*lockDay)
+	day := func(t time.Time) *lockDay {
+		key := startOfDay(t)
+		if days[key] == nil {
+			days[key] = &lockDay{}
+		}
+		return days[key]
+	}
+
+	var total lockDay
+	for i, event := range events {
+		if event.Event != "unlock" {
+			continue
+		}
+		start := event.Time.Add(-time.Duration(event.Seconds) * time.Second)
+		cursor := start
+		if cursor.Before(since) {
+			cursor = since
+		}
+		d := day(cursor)
+		d.locks++
+		total.locks++
+		if event.Kind == lockKindFull {
+			d.full++
+			total.full++
+		}
+		if lockStartedByIdle(events[:i], start) {
+			d.byIdle++
+			total.byIdle++
+		}
+		for cursor.Before(event.Time) {
+			next := startOfDay(cursor).AddDate(0, 0, 1)
+			if next.After(event.Time) {
+				next = event.Time
+			}
+			day(cursor).away += next.Sub(cursor)
+			total.away += next.Sub(cursor)
+			cursor = next
+		}
+	}
+
+	lines := []string{
+		fmt.Sprintf("lock history since %s", since.Format("2006-01-02 15:04")),
+		fmt.Sprintf("%-10s  %8s  %5s  %4s  %4s", "date", "away", "locks", "full", "idle"),
+	}
+	row := func(label string, d lockDay) string {
+		return fmt.Sprintf("%-10s  %8s  %5d  %4d  %4d", label, formatAway(d.away), d.locks, d.full, d.byIdle)
+	}
+	for cursor := startOfDay(since); !cursor.After(now); cursor = cursor.AddDate(0, 0, 1) {
+		d := days[cursor]
+		if d == nil {
+			d = &lockDay{}
+		}
+		lines = append(lines, row(cursor.Format(time.DateOnly), *d))
+	}
+	lines = append(lines, row("total", total))
+	return strings.Join(lines, "\n")
+}
+
+// lockStartedByIdle reports whether the lock event opening the period at start was idle-triggered.
+func lockStartedByIdle(earlier []LockEvent, start time.Time) bool {
+	for i := len(earlier) - 1; i >= 0; i-- {
+		event := earlier[i]
+		if event.Event == "lock" {
+			return event.Trigger == triggerIdle && !event.Time.After(start.Add(time.Second))
+		}
+	}
+	return false
+}
+
+func formatAway(d time.Duration) string {
+	d = d.Round(time.Minute)
+	return fmt.Sprintf("%dh%02dm", int(d.Hours()), int(d.Minutes())%60)
+}
@@ -0,0 +1,342 @@
+package session
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"dotfiles/cmds/internal/config"
+	"dotfiles/cmds/internal/daemon"
+	"dotfiles/cmds/internal/hyprd/hypr"
+	"dotfiles/cmds/internal/hyprd/state"
+)
+
+// ewwdSocket is ewwd's command socket, queried for the timer/alarm countdowns.
+const ewwdSocket = "/tmp/ewwd.sock"
+
+// Clock abstracts time so idle stage scheduling can be driven without real waits.
+type Clock interface {
+	Now() time.Time
+	AfterFunc(d time.Duration, f func()) ClockTimer
+}
+
+// ClockTimer is the cancellable handle returned by Clock.AfterFunc.
+type ClockTimer interface {
+	Stop() bool
+}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time { return time.Now() }
+
+func (systemClock) AfterFunc(d time.Duration, f func()) ClockTimer { return time.AfterFunc(d, f) }
+
+// Idle owns the idle policy: hypridle only reports start/resume, and hyprd decides which
+// stages run and when, vetoing them on state it knows (share, music, fullscreen, alarms).
+//
+// Stages run in order; each is due its configured gap after the previous one actually ran,
+// so a long veto delays the rest of the escalation instead of firing it all at once.
+// gen invalidates timers armed before the latest resume.
+type Idle struct {
+	clock      Clock
+	config     func() config.IdleConfig
+	inhibitors map[string]func(config.IdleConfig) bool
+	act        func(action string) error
+	undo       func(action string) error
+	publish    func(idle bool)
+
+	mu      sync.Mutex
+	idle    bool
+	since   time.Time
+	next    int
+	lastRun time.Time
+	timer   ClockTimer
+	gen     int
+	ran     []string
+	vetoed  []string
+}
+
+func NewIdle(h *hypr.Client, s *state.State, lock *Lock) *Idle {
+	i := &Idle{
+		clock:  systemClock{},
+		config: func() config.IdleConfig { return s.GetConfig().Idle.WithDefaults() },
+	}
+	i.inhibitors = map[string]func(config.IdleConfig) bool{
+		"share": func(config.IdleConfig) bool { return s.GetScreenShare() },
+		"music": func(config.IdleConfig) bool { return playerctlStatus() == "Playing" },
+		"fullscreen": func(cfg config.IdleConfig) bool {
+			return fullscreenActive(h, cfg.Fullscreen)
+		},
+		"alarm": func(cfg config.IdleConfig) bool {
+			return countdownWithin(time.Duration(cfg.AlarmLead) * time.Minute)
+		},
+	}
+	i.act = func(action string) error { return runIdleAction(lock, action) }
+	i.undo = func(action string) error {
+		if action == "dpms" {
+			return runCommand("hyprctl", "dispatch", "dpms", "on")
+		}
+		return nil
+	}
+	return i
+}
+
+// SetClock replaces the wall clock, e.g. with a fake that fires timers on demand.
+func (i *Idle) SetClock(c Clock) {
+	i.mu.Lock()
+	i.clock = c
+	i.mu.Unlock()
+}
+
+// SetPublish registers a callback for idle start and resume.
+func (i *Idle) SetPublish(fn func(idle bool)) {
+	i.mu.Lock()
+	i.publish = fn
+	i.mu.Unlock()
+}
+
+// Active reports whether an idle period is in progress.
+func (i *Idle) Active() bool {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	return i.idle
+}
+
+// Execute routes idle start/resume signals and status.
+func (i *Idle) Execute(arg string) (string, error) {
+	switch strings.TrimSpace(arg) {
+	case "start":
+		return i.Start(), nil
+	case "resume":
+		return i.Resume(), nil
+	case "", "status":
+		return i.Status(), nil
+	default:
+		return "", fmt.Errorf("usage: idle [start|resume|status]")
+	}
+}
+
+// Start begins an idle period and arms the first stage.
+func (i *Idle) Start() string {
+	i.mu.Lock()
+	if i.idle {
+		i.mu.Unlock()
+		return "idle: already idle"
+	}
+	i.idle = true
+	i.since = i.clock.Now()
+	i.lastRun = i.since
+	i.next = 0
+	i.ran = nil
+	i.vetoed = nil
+	i.schedule(i.config())
+	publish := i.publish
+	i.mu.Unlock()
+
+	if publish != nil {
+		publish(true)
+	}
+	return "idle: started"
+}
+
+// Resume cancels pending stages and reverts reversible ones (dpms).
+//
+// Pseudo and full locks are left in place: they are unlocked through their own bindings.
+func (i *Idle) Resume() string {
+	i.mu.Lock()
+	if !i.idle {
+		i.mu.Unlock()
+		return "idle: not idle"
+	}
+	i.idle = false
+	i.gen++
+	if i.timer != nil {
+		i.timer.Stop()
+		i.timer = nil
+	}
+	ran := i.ran
+	elapsed := i.clock.Now().Sub(i.since)
+	i.ran = nil
+	i.vetoed = nil
+	publish := i.publish
+	i.mu.Unlock()
+
+	if publish != nil {
+		publish(false)
+	}
+
+	for _, action := range slices.Backward(ran) {
+		if err := i.undo(action); err != nil {
+			fmt.Fprintf(os.Stderr, "hyprd idle: undo %s: %v\n", action, err)
+		}
+	}
+	return fmt.Sprintf("idle: resumed after %s", elapsed.Round(time.Second))
+}
+
+// Status reports idle time, the pending stage, and any inhibitors currently vetoing it.
+func (i *Idle) Status() string {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	if !i.idle {
+		return "idle: not idle"
+	}
+	cfg := i.config()
+	now := i.clock.Now()
+	line := fmt.Sprintf("idle: %s", now.Sub(i.since).Round(time.Second))
+	if len(i.ran) > 0 {
+		line += " (ran " + strings.Join(i.ran, ", ") + ")"
+	}
+	if i.next >= len(cfg.Stages) {
+		return line + "; no stages left"
+	}
+	stage := cfg.Stages[i.next]
+	if len(i.vetoed) > 0 {
+		return fmt.Sprintf("%s; %s inhibited by %s", line, stage.Action, strings.Join(i.vetoed, ", "))
+	}
+	due := max(i.due(cfg).Sub(now), 0)
+	return fmt.Sprintf("%s; %s in %s", line, stage.Action, due.Round(time.Second))
+}
+
+// due returns when the pending stage should run. Called with i.mu held.
+func (i *Idle) due(cfg config.IdleConfig) time.Time {
+	prev := 0
+	if i.next > 0 && i.next-1 < len(cfg.Stages) {
+		prev = cfg.Stages[i.next-1].After
+	}
+	gap := max(cfg.Stages[i.next].After-prev, 0)
+	return i.lastRun.Add(time.Duration(gap) * time.Second)
+}
+
+// schedule arms a timer for the pending stage. Called with i.mu held.
+func (i *Idle) schedule(cfg config.IdleConfig) {
+	if i.next >= len(cfg.Stages) {
+		i.timer = nil
+		return
+	}
+	gen := i.gen
+	delay := max(i.due(cfg).Sub(i.clock.Now()), 0)
+	i.timer = i.clock.AfterFunc(delay, func() { i.fire(gen) })
+}
+
+// fire evaluates the pending stage's inhibitors and runs it, or re-checks after cfg.Recheck.
+//
+// Inhibitor probes and actions shell out (and locking publishes presence, which reads Active),
+// so they run without i.mu; gen is re-checked after each.
+func (i *Idle) fire(gen int) {
+	cfg := i.config()
+	i.mu.Lock()
+	if !i.idle || gen != i.gen || i.next >= len(cfg.Stages) {
+		i.mu.Unlock()
+		return
+	}
+	stage := cfg.Stages[i.next]
+	i.mu.Unlock()
+
+	var vetoed []string
+	for _, name := range stage.Inhibit {
+		if check, ok := i.inhibitors[name]; ok && check(cfg) {
+			vetoed = append(vetoed, name)
+		}
+	}
+
+	i.mu.Lock()
+	if !i.idle || gen != i.gen {
+		i.mu.Unlock()
+		return
+	}
+	i.vetoed = vetoed
+	if len(vetoed) > 0 {
+		i.timer = i.clock.AfterFunc(time.Duration(cfg.Recheck)*time.Second, func() { i.fire(gen) })
+		i.mu.Unlock()
+		return
+	}
+	i.next++
+	i.lastRun = i.clock.Now()
+	i.mu.Unlock()
+
+	if err := i.act(stage.Action); err != nil {
+		fmt.Fprintf(os.Stderr, "hyprd idle: %s: %v\n", stage.Action, err)
+	}
+
+	// The stage joins ran only once it has acted, so Resume never undoes it first. A resume
+	// that landed mid-action missed it, so it is reverted here instead.
+	i.mu.Lock()
+	if i.idle && gen == i.gen {
+		i.ran = append(i.ran, stage.Action)
+		i.schedule(cfg)
+		i.mu.Unlock()
+		return
+	}
+	i.mu.Unlock()
+	if err := i.undo(stage.Action); err != nil {
+		fmt.Fprintf(os.Stderr, "hyprd idle: undo %s: %v\n", stage.Action, err)
+	}
+}
+
+func runIdleAction(lock *Lock, action string) error {
+	switch action {
+	case "pseudo":
+		_, err := lock.Idle()
+		return err
+	case "lock":
+		_, err := lock.IdleFull()
+		return err
+	case "dpms":
+		return runCommand("hyprctl", "dispatch", "dpms", "off")
+	case "suspend":
+		return runCommand("systemctl", "suspend-then-hibernate")
+	default:
+		return fmt.Errorf("unknown idle action %q", action)
+	}
+}
+
+// fullscreenActive reports whether the focused window is fullscreen and, when classes is set, one of them.
+func fullscreenActive(h *hypr.Client, classes []string) bool {
+	w, err := h.ActiveWindow()
+	if err != nil || w == nil || w.Fullscreen == 0 {
+		return false
+	}
+	return len(classes) == 0 || slices.ContainsFunc(classes, func(class string) bool {
+		return strings.EqualFold(class, w.Class)
+	})
+}
+
+// countdownWithin reports whether a running ewwd timer or alarm finishes within lead.
+//
+// ewwd reports running countdowns as HH:MM remaining; an unreachable ewwd never inhibits.
+func countdownWithin(lead time.Duration) bool {
+	resp, err := daemon.NewClient(ewwdSocket).Send("query timer")
+	if err != nil {
+		return false
+	}
+	var timer struct {
+		Timer       string `json:"timer"`
+		Alarm       string `json:"alarm"`
+		TimerActive bool   `json:"timer_active"`
+		AlarmActive bool   `json:"alarm_active"`
+	}
+	if err := json.Unmarshal([]byte(resp), &timer); err != nil {
+		return false
+	}
+	within := func(active bool, remaining string) bool {
+		if !active {
+			return false
+		}
+		h, m, ok := strings.Cut(remaining, ":")
+		if !ok {
+			return false
+		}
+		hours, errH := strconv.Atoi(h)
+		minutes, errM := strconv.Atoi(m)
+		if errH != nil || errM != nil {
+			return false
+		}
+		return time.Duration(hours*60+minutes)*time.Minute <= lead
+	}
+	return within(timer.TimerActive, timer.Timer) || within(timer.AlarmActive, timer.Alarm)
+}
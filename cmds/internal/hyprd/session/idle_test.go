@@ -0,0 +1,198 @@
+package session
+
+import (
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"dotfiles/cmds/internal/config"
+)
+
+// fakeClock fires AfterFunc callbacks only when Advance moves past their deadline.
+type fakeClock struct {
+	mu     sync.Mutex
+	now    time.Time
+	timers []*fakeTimer
+}
+
+type fakeTimer struct {
+	clock   *fakeClock
+	at      time.Time
+	f       func()
+	stopped bool
+}
+
+func (t *fakeTimer) Stop() bool {
+	t.clock.mu.Lock()
+	defer t.clock.mu.Unlock()
+	was := !t.stopped
+	t.stopped = true
+	return was
+}
+
+func (c *fakeClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+func (c *fakeClock) AfterFunc(d time.Duration, f func()) ClockTimer {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	t := &fakeTimer{clock: c, at: c.now.Add(d), f: f}
+	c.timers = append(c.timers, t)
+	return t
+}
+
+// Advance moves time forward by d, running due callbacks in deadline order outside the lock.
+func (c *fakeClock) Advance(d time.Duration) {
+	c.mu.Lock()
+	end := c.now.Add(d)
+	c.mu.Unlock()
+	for {
+		c.mu.Lock()
+		var next *fakeTimer
+		for _, t := range c.timers {
+			if !t.stopped && !t.at.After(end) && (next == nil || t.at.Before(next.at)) {
+				next = t
+			}
+		}
+		if next == nil {
+			c.now = end
+			c.mu.Unlock()
+			return
+		}
+		next.stopped = true
+		c.now = next.at
+		c.mu.Unlock()
+		next.f()
+	}
+}
+
+// idleLog records act and undo calls in order.
+type idleLog struct {
+	mu    sync.Mutex
+	calls []string
+}
+
+func (l *idleLog) add(call string) {
+	l.mu.Lock()
+	l.calls = append(l.calls, call)
+	l.mu.Unlock()
+}
+
+func (l *idleLog) get() []string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return slices.Clone(l.calls)
+}
+
+func newTestIdle(stages []config.IdleStage, inhibited func() bool) (*Idle, *fakeClock, *idleLog) {
+	clock := &fakeClock{now: time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)}
+	log := &idleLog{}
+	cfg := config.IdleConfig{Stages: stages}.WithDefaults()
+	i := &Idle{
+		config: func() config.IdleConfig { return cfg },
+		inhibitors: map[string]func(config.IdleConfig) bool{
+			"music": func(config.IdleConfig) bool { return inhibited() },
+		},
+		act:  func(action string) error { log.add(action); return nil },
+		undo: func(action string) error { log.add("undo " + action); return nil },
+	}
+	i.SetClock(clock)
+	return i, clock, log
+}
+
+func TestIdleStagesFireInOrder(t *testing.T) {
+	i, clock, log := newTestIdle([]config.IdleStage{
+		{After: 60, Action: "pseudo"},
+		{After: 120, Action: "dpms"},
+	}, func() bool { return false })
+
+	i.Start()
+	clock.Advance(59 * time.Second)
+	if got := log.get(); len(got) != 0 {
+		t.Fatalf("before first stage: got %v", got)
+	}
+	clock.Advance(time.Second)
+	if got := log.get(); !slices.Equal(got, []string{"pseudo"}) {
+		t.Fatalf("at 60s: got %v, want [pseudo]", got)
+	}
+	clock.Advance(60 * time.Second)
+	if got := log.get(); !slices.Equal(got, []string{"pseudo", "dpms"}) {
+		t.Fatalf("at 120s: got %v, want [pseudo dpms]", got)
+	}
+}
+
+func TestIdleVetoDelaysLaterStages(t *testing.T) {
+	inhibited := true
+	i, clock, log := newTestIdle([]config.IdleStage{
+		{After: 10, Action: "pseudo", Inhibit: []string{"music"}},
+		{After: 20, Action: "dpms"},
+	}, func() bool { return inhibited })
+
+	i.Start()
+	clock.Advance(10 * time.Second)
+	if got := log.get(); len(got) != 0 {
+		t.Fatalf("vetoed stage ran: %v", got)
+	}
+	inhibited = false
+	clock.Advance(30 * time.Second) // default recheck
+	if got := log.get(); !slices.Equal(got, []string{"pseudo"}) {
+		t.Fatalf("after recheck: got %v, want [pseudo]", got)
+	}
+	clock.Advance(9 * time.Second) // dpms is due 10s after pseudo actually ran, not at 20s
+	if got := log.get(); len(got) != 1 {
+		t.Fatalf("dpms ran early: %v", got)
+	}
+	clock.Advance(time.Second)
+	if got := log.get(); !slices.Equal(got, []string{"pseudo", "dpms"}) {
+		t.Fatalf("got %v, want [pseudo dpms]", got)
+	}
+}
+
+func TestIdleResumeUndoesAndCancels(t *testing.T) {
+	i, clock, log := newTestIdle([]config.IdleStage{
+		{After: 10, Action: "dpms"},
+		{After: 20, Action: "suspend"},
+	}, func() bool { return false })
+
+	i.Start()
+	clock.Advance(10 * time.Second)
+	i.Resume()
+	clock.Advance(time.Minute)
+	if got := log.get(); !slices.Equal(got, []string{"dpms", "undo dpms"}) {
+		t.Fatalf("got %v, want [dpms undo dpms]", got)
+	}
+	if i.Active() {
+		t.Fatal("still idle after resume")
+	}
+}
+
+// A resume that lands while a stage is acting must not undo it before it has run.
+func TestIdleResumeDuringActUndoesAfterAct(t *testing.T) {
+	i, clock, log := newTestIdle([]config.IdleStage{{After: 10, Action: "dpms"}}, func() bool { return false })
+	acting, release := make(chan struct{}), make(chan struct{})
+	i.act = func(action string) error {
+		close(acting)
+		<-release
+		log.add(action)
+		return nil
+	}
+
+	i.Start()
+	done := make(chan struct{})
+	go func() {
+		clock.Advance(10 * time.Second)
+		close(done)
+	}()
+	<-acting
+	i.Resume()
+	close(release)
+	<-done
+
+	if got := log.get(); !slices.Equal(got, []string{"dpms", "undo dpms"}) {
+		t.Fatalf("got %v, want [dpms undo dpms]", got)
+	}
+}
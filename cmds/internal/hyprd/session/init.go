@@ -2,18 +2,23 @@
 package session
 
 import (
+	"cmp"
 	"context"
+	"errors"
 	"fmt"
 	"net"
 	"os"
 	"os/exec"
-	"sort"
+	"slices"
+	"strings"
+	"sync"
 	"time"
 
 	"dotfiles/cmds/internal/config"
 	"dotfiles/cmds/internal/hyprd/browser"
 	"dotfiles/cmds/internal/hyprd/hypr"
 	"dotfiles/cmds/internal/hyprd/state"
+	"dotfiles/cmds/internal/hyprd/vpn"
 )
 
 var glavaExecs = []string{
@@ -33,9 +38,14 @@ const (
 	bluetoothTryTimeout      = 8 * time.Second
 )
 
-// dispatchStartup runs hardcoded startup commands and optionally connects bluetooth.
+// dispatchStartup starts glava and the hardcoded startup apps, and optionally connects bluetooth.
 func dispatchStartup(h hyprExecutor, bt config.BluetoothConfig) {
 	dispatchGLava(h)
+	dispatchStartupApps(h, bt)
+}
+
+// dispatchStartupApps runs the hardcoded startup commands and optionally connects bluetooth.
+func dispatchStartupApps(h hyprExecutor, bt config.BluetoothConfig) {
 	for _, cmd := range startupExecs {
 		if err := h.Exec(cmd); err != nil {
 			fmt.Fprintf(os.Stderr, "hyprd startup: exec %q: %v\n", cmd, err)
@@ -88,10 +98,12 @@ type NotifyFunc func(app, urgency, title, body string)
 
 // Init drives first-boot session setup: background, optional early lock, network wait, and per-workspace layouts.
 type Init struct {
-	hypr   *hypr.Client
-	state  *state.State
-	notify NotifyFunc
-	lock   *Lock
+	hypr    *hypr.Client
+	state   *state.State
+	notify  NotifyFunc
+	lock    *Lock
+	publish func(BootTimeline)
+	profile string // --profile override; "" selects by rules
 }
 
 func NewInit(h *hypr.Client, s *state.State) *Init {
@@ -106,71 +118,254 @@ func (i *Init) SetNotify(fn NotifyFunc) {
 	i.notify = fn
 }
 
-// Execute runs the full init sequence: background, optional early lock, network, workspace layouts, and startup execs.
+// SetProfile forces a boot profile by name instead of matching profile rules.
+func (i *Init) SetProfile(name string) {
+	i.profile = name
+}
+
+// SetPublish receives a timeline snapshot after every boot step state change.
+func (i *Init) SetPublish(fn func(BootTimeline)) {
+	i.publish = fn
+}
+
+const (
+	bootBackgroundTimeout = 30 * time.Second
+	bootBrowserTimeout    = 60 * time.Second
+	bootSessionTimeout    = 60 * time.Second
+	bootSessionRetries    = 1
+	bootRequiresTimeout   = 90 * time.Second
+	bootVPNTimeout        = 60 * time.Second
+)
+
+// Execute runs the boot DAG: background → [full lock] → network → [vpn, requires] → browsers → init sessions → startup → focus.
 //
-// Inter-dispatch sleeps are tuned for Hyprland to settle; shortening them races layout application.
+// The boot profile is resolved first and decides the sessions, lock, VPNs, wallpaper, and
+// bluetooth device. Init sessions run in parallel since each owns its workspace; their
+// focus-dependent phases serialize through a shared Layout lock. Inter-dispatch sleeps are
+// tuned for Hyprland to settle.
 func (i *Init) Execute() (string, error) {
-	cfg := i.state.GetConfig()
-	init := cfg.Init
-
-	if err := EnsureBGBoot(&cfg.Background); err != nil {
-		return "", fmt.Errorf("background ready before lock: %w", err)
+	plan, err := i.plan(i.state.GetConfig())
+	if err != nil {
+		return "", err
 	}
-	fmt.Println("hyprd init: background ready")
+	i.state.SetBootProfile(plan.profile)
+	if plan.profile != "" {
+		fmt.Fprintf(os.Stderr, "hyprd init: boot profile %s\n", plan.profile)
+	}
+	timeline := runBoot(plan.profile, i.steps(plan), i.publish)
 
-	fullLocked := init.Lock && i.lock != nil
-	if fullLocked {
-		fmt.Println("hyprd init: full-locking")
-		if _, err := i.lock.FullImmediateWait(); err != nil {
-			fmt.Fprintf(os.Stderr, "hyprd init: full-lock: %v\n", err)
+	var failed []string
+	var criticalErr error
+	for _, step := range timeline.Steps {
+		if step.State != bootFailed {
+			continue
 		}
-		fmt.Println("hyprd init: unlocked")
+		failed = append(failed, step.Name)
+		fmt.Fprintf(os.Stderr, "hyprd init: %s: %s\n", step.Name, step.Error)
+		if step.Name == "background" || step.Name == "browsers" {
+			criticalErr = cmp.Or(criticalErr, errors.New(step.Error))
+		}
+	}
+	if criticalErr != nil {
+		return "", criticalErr
 	}
 
-	if init.NetworkTimeout > 0 {
-		if ok := i.waitNetwork(init.NetworkTimeout); ok {
-			fmt.Println("hyprd init: network ready")
-		}
+	fmt.Println("hyprd init: complete")
+	if len(failed) > 0 {
+		return fmt.Sprintf("init: complete (%d failed: %s)", len(failed), strings.Join(failed, ", ")), nil
 	}
+	return "init: complete", nil
+}
 
-	restoreEwwWidgets(true)
+func (i *Init) steps(plan bootPlan) []bootStep {
+	cfg := i.state.GetConfig()
+	init := cfg.Init
+	fullLocked := plan.lock && i.lock != nil
+	initSessions := plan.sessions
 
 	layout := NewLayout(i.hypr, i.state)
-	var initSessions []config.Session
-	for _, s := range cfg.Sessions {
-		if s.Init {
-			initSessions = append(initSessions, s)
-		}
+	layout.focus = &sync.Mutex{}
+
+	steps := []bootStep{{
+		name:     "background",
+		timeout:  bootBackgroundTimeout,
+		critical: true,
+		run: func() error {
+			if plan.wallpaper != "" {
+				if _, err := NewBG(i.state).set(plan.wallpaper); err != nil {
+					fmt.Fprintf(os.Stderr, "hyprd init: profile wallpaper: %v\n", err)
+				}
+			}
+			if err := EnsureBGBoot(i.state); err != nil {
+				return fmt.Errorf("background ready before lock: %w", err)
+			}
+			return nil
+		},
+	}}
+	ready := "background"
+	if fullLocked {
+		steps = append(steps, bootStep{
+			name: "lock",
+			deps: []string{"background"},
+			run: func() error {
+				_, err := i.lock.FullImmediateWait()
+				return err
+			},
+		})
+		ready = "lock"
 	}
-	sort.Slice(initSessions, func(a, b int) bool {
-		return initSessions[a].Workspace < initSessions[b].Workspace
-	})
+	steps = append(steps,
+		bootStep{
+			name:    "network",
+			deps:    []string{ready},
+			timeout: time.Duration(init.NetworkTimeout+5) * time.Second,
+			run: func() error {
+				if init.NetworkTimeout > 0 && !i.waitNetwork(init.NetworkTimeout) {
+					return fmt.Errorf("no network after %ds", init.NetworkTimeout)
+				}
+				return nil
+			},
+		},
+		bootStep{
+			name: "eww",
+			deps: []string{ready},
+			run: func() error {
+				restoreEwwWidgets(true)
+				return nil
+			},
+		},
+	)
 
-	if err := layout.restoreInitBrowsers(initSessions); err != nil {
-		return "", fmt.Errorf("browser restore: %w", err)
+	// Profile VPNs come up before anything that may need them.
+	browserDeps := []string{"network"}
+	if len(plan.vpn) > 0 {
+		browserDeps = append(browserDeps, "vpn")
+		steps = append(steps, bootStep{
+			name:    "vpn",
+			deps:    []string{"network"},
+			timeout: bootVPNTimeout,
+			run: func() error {
+				v := vpn.New(&cfg.VPN)
+				var errs []error
+				for _, name := range plan.vpn {
+					if _, err := v.Ensure(name); err != nil {
+						errs = append(errs, fmt.Errorf("%s: %w", name, err))
+					}
+				}
+				return errors.Join(errs...)
+			},
+		})
 	}
 
-	for _, session := range initSessions {
-		result, err := layout.openSession(session)
-		if err != nil {
-			fmt.Printf("hyprd init: ws%d: %v\n", session.Workspace, err)
-		} else {
-			fmt.Printf("hyprd init: %s\n", result)
+	// Sessions are checked concurrently. Those whose prerequisites fail (or are still being
+	// checked when the step times out) are left out of the batch browser restore; their own
+	// session step re-checks and either fails with the message or restores the browser
+	// itself. Sessions that passed skip the re-check.
+	var metMu sync.Mutex
+	met := make(map[string]bool, len(initSessions))
+	browserSessions := func() []config.Session { return initSessions }
+	if slices.ContainsFunc(initSessions, func(s config.Session) bool { return !s.Requires.IsZero() }) {
+		for _, s := range initSessions {
+			met[s.Name] = s.Requires.IsZero()
+		}
+		browserSessions = func() []config.Session {
+			metMu.Lock()
+			defer metMu.Unlock()
+			return slices.DeleteFunc(slices.Clone(initSessions), func(s config.Session) bool { return !met[s.Name] })
+		}
+		browserDeps = append(browserDeps, "requires")
+		steps = append(steps, bootStep{
+			name:    "requires",
+			deps:    slices.Clone(browserDeps),
+			timeout: bootRequiresTimeout,
+			run: func() error {
+				errs := make([]error, len(initSessions))
+				var wg sync.WaitGroup
+				for idx, s := range initSessions {
+					if s.Requires.IsZero() {
+						continue
+					}
+					wg.Go(func() {
+						if err := ensureRequires(cfg, s); err != nil {
+							errs[idx] = err
+							if i.notify != nil {
+								i.notify("attention", "critical", "Session "+s.Name, err.Error())
+							}
+							return
+						}
+						metMu.Lock()
+						met[s.Name] = true
+						metMu.Unlock()
+					})
+				}
+				wg.Wait()
+				return errors.Join(errs...)
+			},
+		})
+		layout.requiresMet = func(name string) bool {
+			metMu.Lock()
+			defer metMu.Unlock()
+			return met[name]
 		}
 	}
+	steps = append(steps, bootStep{
+		name:     "browsers",
+		deps:     browserDeps,
+		timeout:  bootBrowserTimeout,
+		critical: true,
+		run: func() error {
+			if err := layout.restoreInitBrowsers(browserSessions()); err != nil {
+				return fmt.Errorf("browser restore: %w", err)
+			}
+			return nil
+		},
+	})
 
-	if !fullLocked {
-		dispatchStartup(i.hypr, cfg.Bluetooth)
+	var sessionSteps []string
+	for _, s := range initSessions {
+		name := "session:" + s.Name
+		sessionSteps = append(sessionSteps, name)
+		steps = append(steps, bootStep{
+			name:    name,
+			deps:    []string{"browsers"},
+			timeout: bootSessionTimeout,
+			retries: bootSessionRetries,
+			run: func() error {
+				result, err := layout.openSession(s)
+				if err != nil {
+					return fmt.Errorf("ws%d: %w", s.Workspace, err)
+				}
+				fmt.Printf("hyprd init: %s\n", result)
+				return nil
+			},
+		})
 	}
 
-	if init.Workspace > 0 {
-		if err := i.hypr.FocusWorkspace(init.Workspace); err != nil {
-			return "", fmt.Errorf("focus initial workspace %d: %w", init.Workspace, err)
-		}
+	// Startup execs land on the focused workspace, so they wait for every session to settle.
+	startupDeps := append([]string{ready}, sessionSteps...)
+	if !fullLocked {
+		steps = append(steps, bootStep{
+			name: "startup",
+			deps: startupDeps,
+			run: func() error {
+				dispatchStartup(i.hypr, plan.bluetooth)
+				return nil
+			},
+		})
 	}
-
-	fmt.Println("hyprd init: complete")
-	return "init: complete", nil
+	if plan.workspace > 0 {
+		steps = append(steps, bootStep{
+			name: "focus",
+			deps: slices.Concat(startupDeps, []string{"startup"}),
+			run: func() error {
+				if err := i.hypr.FocusWorkspace(plan.workspace); err != nil {
+					return fmt.Errorf("focus initial workspace %d: %w", plan.workspace, err)
+				}
+				return nil
+			},
+		})
+	}
+	return steps
 }
 
 func (l *Layout) restoreInitBrowsers(sessions []config.Session) error {
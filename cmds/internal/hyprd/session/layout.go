@@ -7,6 +7,8 @@ import (
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"dotfiles/cmds/internal/config"
@@ -22,28 +24,45 @@ const (
 )
 
 // Layout opens and arranges per-workspace sessions defined in config.
+//
+// focus, when set, serializes the focus-dependent phases of concurrent openSession calls;
+// waiting for spawned windows to map runs outside it. requiresMet, when set, reports
+// sessions whose prerequisites boot already verified, so opening them skips the re-check.
 type Layout struct {
 	hypr                  *hypr.Client
 	state                 *state.State
 	batchRestoredBrowsers map[string]struct{}
+	focus                 sync.Locker
+	requiresMet           func(name string) bool
 }
 
 func NewLayout(h *hypr.Client, s *state.State) *Layout {
 	return &Layout{hypr: h, state: s}
 }
 
-// Execute dispatches: "list", "set <ws> <name>", a workspace number, or a session name.
+// Execute dispatches: "list", "set <ws> <name>", "save <name> ...", "close ...", "new <name> ...",
+// a workspace number, or a session name.
 func (l *Layout) Execute(arg string) (string, error) {
 	cfg := l.state.GetConfig()
 	sessions := cfg.Sessions
 
 	parts := strings.Fields(arg)
+	// Subcommands shadow session names; config.LayoutVerbs lists them for the name checks.
 	if len(parts) == 0 || arg == "--list" || arg == "-l" || arg == "list" {
 		return l.listByWorkspace(sessions), nil
 	}
 	if parts[0] == "set" {
 		return l.setActive(parts[1:], sessions)
 	}
+	if parts[0] == "save" {
+		return l.save(parts[1:])
+	}
+	if parts[0] == "close" {
+		return l.close(parts[1:])
+	}
+	if parts[0] == "new" {
+		return l.newFromTemplate(parts[1:])
+	}
 	if ws, err := strconv.Atoi(parts[0]); err == nil {
 		return l.openByWorkspace(ws, sessions)
 	}
@@ -119,10 +138,49 @@ func (l *Layout) listByWorkspace(sessions config.SessionsConfig) string {
 	return strings.Join(lines, "\n")
 }
 
+// holdFocus takes the focus lock if one is set and returns an idempotent release.
+func (l *Layout) holdFocus() func() {
+	if l.focus == nil {
+		return func() {}
+	}
+	l.focus.Lock()
+	return sync.OnceFunc(l.focus.Unlock)
+}
+
+// sessionPublish receives every session a Layout opens; see SetSessionPublish.
+var sessionPublish atomic.Pointer[func(config.Session)]
+
+// SetSessionPublish registers a callback run after any Layout opens a session, including
+// boot sessions and rendered templates and projects. Dry-run layouts don't call it.
+func SetSessionPublish(fn func(config.Session)) {
+	sessionPublish.Store(&fn)
+}
+
+// openSession arranges s on its workspace and reports it to the session publish callback.
 func (l *Layout) openSession(s config.Session) (string, error) {
+	result, err := l.arrangeSession(s)
+	if err != nil {
+		return "", err
+	}
+	if fn := sessionPublish.Load(); fn != nil && *fn != nil && !l.hypr.IsDryRun() {
+		(*fn)(s)
+	}
+	return result, nil
+}
+
+func (l *Layout) arrangeSession(s config.Session) (string, error) {
 	if err := validateSessionBrowser(s); err != nil {
 		return "", err
 	}
+	verified := l.requiresMet != nil && l.requiresMet(s.Name)
+	if !s.Requires.IsZero() && !verified && !l.hypr.Skip("requires", "prerequisites of session "+s.Name) {
+		if err := ensureRequires(l.state.GetConfig(), s); err != nil {
+			return "", err
+		}
+	}
+
+	release := l.holdFocus()
+	defer func() { release() }()
 
 	if err := l.hypr.FocusWorkspace(s.Workspace); err != nil {
 		return "", fmt.Errorf("focus workspace %d: %w", s.Workspace, err)
@@ -165,7 +223,12 @@ func (l *Layout) openSession(s config.Session) (string, error) {
 			roles = append(roles, "browser")
 		}
 
+		release()
 		windowsByRole := l.waitForSessionRoles(s, roles, sessionWindowTimeout)
+		release = l.holdFocus()
+		if err := l.refocus(s); err != nil {
+			return "", err
+		}
 		if commandWindow := windowsByRole[s.Name]; commandWindow != nil {
 			if err := l.arrangePair(s, commandWindow, windowsByRole["browser"]); err != nil {
 				return "", err
@@ -200,7 +263,12 @@ func (l *Layout) openSession(s config.Session) (string, error) {
 			return "", err
 		}
 	}
+	release()
 	windowsByRole := l.waitForSessionRoles(s, s.Body, sessionWindowTimeout)
+	release = l.holdFocus()
+	if err := l.refocus(s); err != nil {
+		return "", err
+	}
 
 	if err := l.hypr.LayoutMsg(fmt.Sprintf("mfact exact %s", cfg.Windows.Split.Default)); err != nil {
 		return "", fmt.Errorf("set layout split: %w", err)
@@ -218,6 +286,16 @@ func (l *Layout) openSession(s config.Session) (string, error) {
 	return l.sessionResult(s, s.Body, windowsByRole), nil
 }
 
+// refocus returns to the session's workspace after the focus lock was released: sessions
+// opening in parallel may have moved focus while windows were mapping, and the layout and
+// monocle calls that follow act on the focused workspace.
+func (l *Layout) refocus(s config.Session) error {
+	if err := l.hypr.FocusWorkspace(s.Workspace); err != nil {
+		return fmt.Errorf("refocus workspace %d: %w", s.Workspace, err)
+	}
+	return nil
+}
+
 func (l *Layout) applyMonocle(wsID int) error {
 	active, err := l.hypr.ActiveWindow()
 	if err != nil || active == nil {
@@ -268,6 +346,9 @@ func (l *Layout) withSessionLaunchEnv(s config.Session, bodyName, cmd, homeDir s
 }
 
 func (l *Layout) launchSessionBrowser(s config.Session) error {
+	if l.hypr.Skip("browser", fmt.Sprintf("launch %s browser (snapshot %q, %d urls)", s.Name, s.Browser.Snapshot, len(s.Browser.AllURLs()))) {
+		return nil
+	}
 	b := browser.NewBrowser(l.hypr, l.state)
 
 	if b.UsesExactRestore(s.Browser) {
@@ -292,17 +373,24 @@ func (l *Layout) launchSessionBrowser(s config.Session) error {
 		}
 		return nil
 	}
+	if urls := s.Browser.AllURLs(); len(urls) > 0 {
+		return b.LaunchURLs(urls, s.Workspace)
+	}
 	return fmt.Errorf("session %q browser layout requires exact browser snapshot restore", s.Name)
 }
 
+// validateSessionBrowser accepts an exact snapshot restore or, typically from templates, a URL list.
 func validateSessionBrowser(s config.Session) error {
 	if !sessionUsesBrowser(s) {
 		return nil
 	}
-	if strings.TrimSpace(s.Browser.Snapshot) == "" {
-		return fmt.Errorf("session %q browser layout requires explicit browser snapshot config", s.Name)
+	if (&browser.Browser{}).UsesExactRestore(s.Browser) {
+		return nil
+	}
+	if strings.TrimSpace(s.Browser.Snapshot) == "" && len(s.Browser.AllURLs()) == 0 {
+		return fmt.Errorf("session %q browser layout requires a browser snapshot or urls", s.Name)
 	}
-	if !(&browser.Browser{}).UsesExactRestore(s.Browser) {
+	if len(s.Browser.AllURLs()) == 0 {
 		return fmt.Errorf("session %q browser layout requires exact browser snapshot restore", s.Name)
 	}
 	return nil
@@ -401,7 +489,7 @@ func (l *Layout) waitForSessionRoles(s config.Session, roles []string, timeout t
 				return found
 			}
 		}
-		if !time.Now().Before(deadline) {
+		if !time.Now().Before(deadline) || l.hypr.IsDryRun() { // nothing spawns in a dry run
 			return found
 		}
 		time.Sleep(250 * time.Millisecond)
@@ -517,6 +605,9 @@ func (l *Layout) focusWindow(address string, timeout time.Duration) (bool, error
 	if err := l.hypr.FocusWindow(address); err != nil {
 		return false, fmt.Errorf("focus window %s: %w", address, err)
 	}
+	if l.hypr.IsDryRun() {
+		return true, nil
+	}
 	deadline := time.Now().Add(timeout)
 	for time.Now().Before(deadline) {
 		active, err := l.hypr.ActiveWindow()
@@ -37,13 +37,18 @@ type hyprIPC interface {
 // Lock owns pseudo-lock and full-lock lifecycles: visual blackout, audio/notification pause, and restore.
 //
 // Serialized by mu; saved != nil means a lock is active, inFull means hyprlock is blocking.
+// lockedAt and kind describe the active lock period for the transitions sent to publish.
 type Lock struct {
 	hypr            hyprIPC
 	state           *state.State
+	publish         func(LockEvent)
+	dispatch        func(string) string // daemon command handler for `hyprd:` hooks
 	mu              sync.Mutex
 	saved           *lockState
 	inFull          bool
 	idleUnlockAfter time.Time
+	lockedAt        time.Time
+	kind            string
 }
 
 type lockState struct {
@@ -56,8 +61,32 @@ func NewLock(h *hypr.Client, s *state.State) *Lock {
 	return &Lock{hypr: h, state: s}
 }
 
-// Execute routes pseudo, idle pseudo-lock, unlock, idle unlock, and full lock.
+// SetPublish registers a callback for lock, escalate, and unlock transitions.
+func (l *Lock) SetPublish(fn func(LockEvent)) {
+	l.mu.Lock()
+	l.publish = fn
+	l.mu.Unlock()
+}
+
+// Presence reports "locked" while hyprlock is up, "away" during a pseudo-lock, else "active".
+func (l *Lock) Presence() string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	switch {
+	case l.inFull:
+		return PresenceLocked
+	case l.saved != nil:
+		return PresenceAway
+	default:
+		return PresenceActive
+	}
+}
+
+// Execute routes pseudo, idle pseudo-lock, unlock, idle unlock, full lock, and the history report.
 func (l *Lock) Execute(arg string) (string, error) {
+	if fields := strings.Fields(arg); len(fields) > 0 && fields[0] == "history" {
+		return lockHistory(fields[1:])
+	}
 	switch strings.TrimSpace(arg) {
 	case "", "pseudo":
 		return l.Pseudo()
@@ -70,7 +99,7 @@ func (l *Lock) Execute(arg string) (string, error) {
 	case "full":
 		return l.Full()
 	default:
-		return "", fmt.Errorf("usage: lock [pseudo|idle|unlock|idle-unlock|full]")
+		return "", fmt.Errorf("usage: lock [pseudo|idle|unlock|idle-unlock|full|history [--since <when>]]")
 	}
 }
 
@@ -109,21 +138,25 @@ func (l *Lock) enterPseudo(kind string, idle bool) (string, error) {
 		return "", fmt.Errorf("lock: enter pseudolock: %w", err)
 	}
 
+	trigger := triggerManual
 	if idle {
 		l.idleUnlockAfter = time.Now().Add(idleUnlockSuppress)
+		trigger = triggerIdle
 	} else {
 		l.idleUnlockAfter = time.Time{}
 	}
 	l.saved = saved
+	event := l.beginPeriod(lockKindPseudo, trigger)
 	l.mu.Unlock()
 
+	l.emit(event)
 	l.enterBlackout(saved)
 	return "lock: " + kind, nil
 }
 
 // Unlock exits pseudo-lock; refuses while hyprlock is active.
 func (l *Lock) Unlock() (string, error) {
-	return l.unlock()
+	return l.unlock(triggerManual)
 }
 
 // IdleUnlock exits an idle pseudo-lock unless this is hypridle's synthetic
@@ -135,10 +168,10 @@ func (l *Lock) IdleUnlock() (string, error) {
 		return "lock: idle unlock suppressed", nil
 	}
 	l.mu.Unlock()
-	return l.unlock()
+	return l.unlock(triggerIdle)
 }
 
-func (l *Lock) unlock() (string, error) {
+func (l *Lock) unlock(trigger string) (string, error) {
 	l.mu.Lock()
 	if l.inFull {
 		l.mu.Unlock()
@@ -156,32 +189,39 @@ func (l *Lock) unlock() (string, error) {
 	saved := l.saved
 	l.saved = nil
 	l.idleUnlockAfter = time.Time{}
+	event := l.endPeriod(trigger)
 	l.mu.Unlock()
 
+	l.emit(event)
 	if err := l.exitBlackout(saved, saved.musicPlaying); err != nil {
 		return "lock: unlocked", err
 	}
 	return "lock: unlocked", nil
 }
 
-// Full runs hyprlock asynchronously with pre/post blackout hooks.
+// Full runs hyprlock asynchronously with pre/post blackout and lock hooks.
 func (l *Lock) Full() (string, error) {
-	return l.full(fullLockDelay, fullLockGrace, true, true)
+	return l.full(fullLockDelay, fullLockGrace, true, true, triggerManual)
+}
+
+// IdleFull is Full escalated by the idle policy rather than the user.
+func (l *Lock) IdleFull() (string, error) {
+	return l.full(fullLockDelay, fullLockGrace, true, true, triggerIdle)
 }
 
 // FullImmediate runs hyprlock without startup delay or grace, for boot-time authentication.
 func (l *Lock) FullImmediate() (string, error) {
-	return l.full(0, 0, true, true)
+	return l.full(0, 0, true, true, triggerBoot)
 }
 
 // FullImmediateWait runs the boot-time full lock synchronously so startup work
 // does not open private workspace layouts behind the lock screen.
 func (l *Lock) FullImmediateWait() (string, error) {
-	return l.fullBlocking(0, 0, true, false)
+	return l.fullBlocking(0, 0, true, false, triggerBoot)
 }
 
-func (l *Lock) full(delay, grace time.Duration, loadSSH, restoreWidgets bool) (string, error) {
-	saved, result, err := l.startFull(restoreWidgets)
+func (l *Lock) full(delay, grace time.Duration, loadSSH, restoreWidgets bool, trigger string) (string, error) {
+	saved, result, err := l.startFull(restoreWidgets, trigger)
 	if saved == nil || err != nil {
 		return result, err
 	}
@@ -190,8 +230,8 @@ func (l *Lock) full(delay, grace time.Duration, loadSSH, restoreWidgets bool) (s
 	return "lock: full", nil
 }
 
-func (l *Lock) fullBlocking(delay, grace time.Duration, loadSSH, restoreWidgets bool) (string, error) {
-	saved, result, err := l.startFull(restoreWidgets)
+func (l *Lock) fullBlocking(delay, grace time.Duration, loadSSH, restoreWidgets bool, trigger string) (string, error) {
+	saved, result, err := l.startFull(restoreWidgets, trigger)
 	if saved == nil || err != nil {
 		return result, err
 	}
@@ -200,7 +240,7 @@ func (l *Lock) fullBlocking(delay, grace time.Duration, loadSSH, restoreWidgets
 	return "lock: full", nil
 }
 
-func (l *Lock) startFull(restoreWidgets bool) (*lockState, string, error) {
+func (l *Lock) startFull(restoreWidgets bool, trigger string) (*lockState, string, error) {
 	l.mu.Lock()
 	if l.inFull {
 		l.mu.Unlock()
@@ -226,8 +266,10 @@ func (l *Lock) startFull(restoreWidgets bool) (*lockState, string, error) {
 
 	saved := l.saved
 	l.inFull = true
+	event := l.beginPeriod(lockKindFull, trigger)
 	l.mu.Unlock()
 
+	l.emit(event)
 	if needsBlackout {
 		l.enterBlackout(saved)
 	}
@@ -238,18 +280,21 @@ func (l *Lock) runHyprlock(saved *lockState, delay, grace time.Duration, loadSSH
 	if delay > 0 {
 		time.Sleep(delay)
 	}
-	if loadSSH {
+	lc := l.state.GetConfig().Lock
+	if loadSSH && !lc.Disabled("ssh") {
 		flag := filepath.Join(runtimeDir(), pamLoadFlag)
 		if f, err := os.Create(flag); err == nil {
 			f.Close()
 			defer os.Remove(flag)
 		}
 	}
+	l.runHooks(phasePreLock, lc.Hooks.PreLock)
 
 	cmd := exec.Command("hyprlock", "--grace", strconv.Itoa(int(grace/time.Second)))
 	if err := cmd.Start(); err == nil {
 		cmd.Wait()
 	}
+	l.runHooks(phasePostUnlock, lc.Hooks.PostUnlock)
 
 	l.mu.Lock()
 	l.inFull = false
@@ -260,8 +305,10 @@ func (l *Lock) runHyprlock(saved *lockState, delay, grace time.Duration, loadSSH
 	l.saved = nil
 	l.idleUnlockAfter = time.Time{}
 	resumeMusic := saved.musicPlaying
+	event := l.endPeriod(triggerAuth)
 	l.mu.Unlock()
 
+	l.emit(event)
 	if err := l.exitBlackout(saved, resumeMusic); err != nil {
 		fmt.Fprintf(os.Stderr, "hyprd lock: unlock after hyprlock: %v\n", err)
 	}
@@ -291,11 +338,38 @@ func (l *Lock) capture() *lockState {
 	}
 }
 
+// enterBlackout runs the enabled pre_pseudo built-ins, then the configured hooks, stopping
+// early if the lock ends meanwhile.
 func (l *Lock) enterBlackout(saved *lockState) {
-	if !l.active(saved) {
-		return
+	lc := l.state.GetConfig().Lock
+	builtins := []struct {
+		name string
+		run  func()
+	}{
+		{"music", func() { l.pauseMusic(saved) }},
+		{"glava", func() { exec.Command("killall", "glava").Run() }},
+		{"notifications", func() {
+			exec.Command("dunstctl", "close-all").Run()
+			exec.Command("dunstctl", "set-paused", "true").Run()
+		}},
+		{"eww", func() { l.closeEwwWidgets(saved) }},
+	}
+	for _, b := range builtins {
+		if lc.Disabled(b.name) {
+			continue
+		}
+		if !l.active(saved) {
+			return
+		}
+		b.run()
 	}
+	if l.active(saved) {
+		l.runHooks(phasePrePseudo, lc.Hooks.PrePseudo)
+	}
+}
 
+// pauseMusic remembers whether a player was playing so unlock can resume it.
+func (l *Lock) pauseMusic(saved *lockState) {
 	musicPlaying := playerctlStatus() == "Playing"
 	l.mu.Lock()
 	active := l.saved == saved
@@ -303,27 +377,9 @@ func (l *Lock) enterBlackout(saved *lockState) {
 		saved.musicPlaying = musicPlaying
 	}
 	l.mu.Unlock()
-	if !active {
-		return
-	}
-
-	exec.Command("killall", "glava").Run()
-	if !l.active(saved) {
-		return
-	}
-	exec.Command("dunstctl", "close-all").Run()
-	if !l.active(saved) {
-		return
-	}
-	exec.Command("dunstctl", "set-paused", "true").Run()
-	if !l.active(saved) {
-		return
-	}
-	exec.Command("playerctl", "pause").Run()
-	if !l.active(saved) {
-		return
+	if active {
+		exec.Command("playerctl", "pause").Run()
 	}
-	l.closeEwwWidgets(saved)
 }
 
 func (l *Lock) active(saved *lockState) bool {
@@ -349,28 +405,37 @@ func (l *Lock) closeEwwWidgets(saved *lockState) {
 	}
 }
 
-// exitBlackout restores workspace, reopens eww/glava, reconnects bluetooth, and unpauses dunst.
+// exitBlackout restores the workspace and background, runs the enabled post_pseudo built-ins
+// (glava, startup apps and bluetooth, eww, music, dunst), then the configured hooks.
 func (l *Lock) exitBlackout(saved *lockState, resumeMusic bool) error {
 	cfg := l.state.GetConfig()
 	if err := l.hypr.FocusWorkspace(saved.workspace); err != nil {
 		return fmt.Errorf("lock: restore workspace %d: %w", saved.workspace, err)
 	}
-	if err := EnsureBG(&cfg.Background); err != nil {
+	if err := EnsureBG(l.state); err != nil {
 		fmt.Fprintf(os.Stderr, "hyprd lock: background: %v\n", err)
 	}
 
-	dispatchStartup(l.hypr, cfg.Bluetooth)
-	if saved.restoreWidgets {
+	lc := cfg.Lock
+	if !lc.Disabled("glava") {
+		dispatchGLava(l.hypr)
+	}
+	if !lc.Disabled("startup") {
+		dispatchStartupApps(l.hypr, profileBluetooth(cfg, l.state.GetBootProfile()))
+	}
+	if saved.restoreWidgets && !lc.Disabled("eww") {
 		restoreEwwWidgets(false)
 	}
-
 	if resumeMusic {
 		exec.Command("playerctl", "play").Run()
 	}
-	// Delay dunst unpause so queued notifications don't clobber eww startup.
-	time.AfterFunc(time.Second, func() {
-		exec.Command("dunstctl", "set-paused", "false").Run()
-	})
+	if !lc.Disabled("notifications") {
+		// Delay dunst unpause so queued notifications don't clobber eww startup.
+		time.AfterFunc(time.Second, func() {
+			exec.Command("dunstctl", "set-paused", "false").Run()
+		})
+	}
+	l.runHooks(phasePostPseudo, lc.Hooks.PostPseudo)
 	return nil
 }
 
@@ -0,0 +1,78 @@
+package session
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"dotfiles/cmds/internal/config"
+	"dotfiles/cmds/internal/hyprd/hooks"
+)
+
+// Lock hook phases; config.LockHooks documents when each runs.
+const (
+	phasePrePseudo  = "pre_pseudo"
+	phasePreLock    = "pre_lock"
+	phasePostUnlock = "post_unlock"
+	phasePostPseudo = "post_pseudo"
+)
+
+const lockHookTimeout = 5 * time.Second
+
+// lockHookEvent is the JSON a `run` lock hook reads on stdin.
+type lockHookEvent struct {
+	Event string    `json:"event"` // the phase, e.g. pre_lock
+	Time  time.Time `json:"time"`
+}
+
+// SetDispatch routes `hyprd:` lock hooks through the daemon's command handler.
+func (l *Lock) SetDispatch(fn func(string) string) {
+	l.mu.Lock()
+	l.dispatch = fn
+	l.mu.Unlock()
+}
+
+// runHooks runs a phase's hooks in order. Failures are logged and never abort the lock.
+func (l *Lock) runHooks(phase string, list []config.LockHook) {
+	for _, hook := range list {
+		if err := l.runHook(phase, hook); err != nil {
+			fmt.Fprintf(os.Stderr, "hyprd lock: %s hook: %v\n", phase, err)
+		}
+	}
+}
+
+func (l *Lock) runHook(phase string, hook config.LockHook) error {
+	timeout := lockHookTimeout
+	if hook.Timeout > 0 {
+		timeout = time.Duration(hook.Timeout) * time.Second
+	}
+
+	if hook.Run != "" {
+		ev := lockHookEvent{Event: phase, Time: time.Now()}
+		out, err := hooks.Run(hook.Run, []string{"HYPRD_EVENT=" + phase}, ev, timeout)
+		if err != nil {
+			return fmt.Errorf("%w: %s", err, strings.TrimSpace(string(out)))
+		}
+		return nil
+	}
+
+	l.mu.Lock()
+	dispatch := l.dispatch
+	l.mu.Unlock()
+	if hook.Hyprd == "" || dispatch == nil {
+		return errors.New("hook needs run or hyprd")
+	}
+	done := make(chan string, 1)
+	go func() { done <- dispatch(hook.Hyprd) }()
+	select {
+	case result := <-done:
+		if msg, ok := strings.CutPrefix(result, "error: "); ok {
+			return fmt.Errorf("hyprd %s: %s", hook.Hyprd, msg)
+		}
+		return nil
+	case <-time.After(timeout):
+		return fmt.Errorf("hyprd %s: timed out after %s", hook.Hyprd, timeout)
+	}
+}
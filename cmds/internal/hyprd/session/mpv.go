@@ -0,0 +1,57 @@
+package session
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"net"
+	"sync/atomic"
+	"time"
+)
+
+const mpvIPCTimeout = 2 * time.Second
+
+var mpvRequestID atomic.Int64
+
+// mpvCommand sends one JSON IPC command to mpv on socket and waits for its reply.
+//
+// mpv interleaves event lines on the same connection, so the reply is matched by request_id.
+func mpvCommand(socket string, args ...any) error {
+	conn, err := net.DialTimeout("unix", socket, 200*time.Millisecond)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	if err := conn.SetDeadline(time.Now().Add(mpvIPCTimeout)); err != nil {
+		return err
+	}
+
+	id := mpvRequestID.Add(1)
+	req, err := json.Marshal(map[string]any{"command": args, "request_id": id})
+	if err != nil {
+		return err
+	}
+	if _, err := conn.Write(append(req, '\n')); err != nil {
+		return err
+	}
+
+	scanner := bufio.NewScanner(conn)
+	for scanner.Scan() {
+		var resp struct {
+			RequestID int64  `json:"request_id"`
+			Error     string `json:"error"`
+			Event     string `json:"event"`
+		}
+		if json.Unmarshal(scanner.Bytes(), &resp) != nil || resp.Event != "" || resp.RequestID != id {
+			continue
+		}
+		if resp.Error != "success" {
+			return fmt.Errorf("mpv %v: %s", args[0], resp.Error)
+		}
+		return nil
+	}
+	if err := scanner.Err(); err != nil {
+		return err
+	}
+	return fmt.Errorf("mpv %v: connection closed", args[0])
+}
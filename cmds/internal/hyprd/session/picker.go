@@ -1,6 +1,7 @@
 package session
 
 import (
+	"dotfiles/cmds/internal/hyprd/browser"
 	"dotfiles/cmds/internal/hyprd/hypr"
 	"dotfiles/cmds/internal/hyprd/state"
 	"encoding/json"
@@ -15,6 +16,9 @@ import (
 )
 
 // Picker manages the layout-picker overlay driven by Hyprland submap keys and rendered by eww.
+//
+// A non-empty filter query swaps the workspace × session grid for a single ranked list across
+// all workspaces; every move then steps through that list and the ws cursor follows the selection.
 type Picker struct {
 	hypr  *hypr.Client
 	state *state.State
@@ -26,6 +30,8 @@ type Picker struct {
 	selecting bool             // true once the user starts cycling session options
 	confirmed bool             // true during the brief green-flash after confirm
 	cache     map[int][]string // ws → sorted session names
+	query     string           // active filter text; "" shows the grid
+	ranked    []pickerMatch    // filter results, best first; si indexes this while filtering
 }
 
 type pickerPayload struct {
@@ -34,12 +40,31 @@ type pickerPayload struct {
 	Sessions  []pickerSession `json:"sessions"`
 	Count     int             `json:"count"`
 	Confirmed bool            `json:"confirmed"`
+	Query     string          `json:"query"`
+	Preview   *pickerPreview  `json:"preview,omitempty"`
 }
 
 type pickerSession struct {
-	Name     string `json:"name"`
-	Selected bool   `json:"selected"`
-	Active   bool   `json:"active"`
+	Name     string   `json:"name"`
+	Selected bool     `json:"selected"`
+	Active   bool     `json:"active"`
+	WS       int      `json:"ws,omitempty"`      // set while filtering, when rows span workspaces
+	Rank     int      `json:"rank,omitempty"`    // 1-based filter rank
+	Score    int      `json:"score,omitempty"`   // fuzzy score behind the rank
+	Matched  []string `json:"matched,omitempty"` // fields the query hit: name, project, role
+}
+
+// pickerPreview describes what confirming the selected session will spawn.
+type pickerPreview struct {
+	Name        string   `json:"name"`
+	WS          int      `json:"ws"`
+	Project     string   `json:"project,omitempty"`
+	Roles       []string `json:"roles"`
+	Tabs        []string `json:"tabs,omitempty"` // role:profile pairs
+	Command     string   `json:"command,omitempty"`
+	Browser     string   `json:"browser,omitempty"`
+	BrowserTabs int      `json:"browser_tabs,omitempty"`
+	Summary     string   `json:"summary"` // one-line rendering for the eww label
 }
 
 func NewPicker(h *hypr.Client, s *state.State) *Picker {
@@ -62,11 +87,16 @@ func (p *Picker) Execute(arg string) (string, error) {
 		return p.move(1, 0)
 	case "confirm":
 		return p.confirm()
+	case "filter":
+		return p.filter("")
 	default:
 		if rest, ok := strings.CutPrefix(arg, "ws "); ok {
 			return p.jumpWS(rest)
 		}
-		return "", fmt.Errorf("usage: picker [open|close|left|right|up|down|confirm|ws <n>]")
+		if rest, ok := strings.CutPrefix(arg, "filter "); ok {
+			return p.filter(rest)
+		}
+		return "", fmt.Errorf("usage: picker [open|close|left|right|up|down|confirm|ws <n>|filter [text]]")
 	}
 }
 
@@ -105,6 +135,8 @@ func (p *Picker) open() (string, error) {
 	p.si = p.activeIndex(p.ws)
 	p.selecting = false
 	p.confirmed = false
+	p.query = ""
+	p.ranked = nil
 	p.active = true
 
 	if err := p.hypr.Submap("picker"); err != nil {
@@ -148,6 +180,15 @@ func (p *Picker) move(dws, dsi int) (string, error) {
 		return "picker: not open", nil
 	}
 
+	if p.query != "" {
+		if len(p.ranked) > 0 {
+			p.si = wrapIndex(p.si+dws+dsi, len(p.ranked))
+			p.ws = p.ranked[p.si].WS
+		}
+		p.pushState()
+		return fmt.Sprintf("picker: match %d/%d", p.si+1, len(p.ranked)), nil
+	}
+
 	if dws != 0 {
 		p.ws += dws
 		if p.ws < 2 {
@@ -191,11 +232,41 @@ func (p *Picker) jumpWS(arg string) (string, error) {
 	}
 
 	p.ws = ws
+	p.query = ""
+	p.ranked = nil
 	p.selectFirst(ws)
 	p.pushState()
 	return fmt.Sprintf("picker: jumped to ws%d", ws), nil
 }
 
+// filter re-ranks sessions for text and selects the best match; empty text restores the grid.
+func (p *Picker) filter(text string) (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if !p.active {
+		return "picker: not open", nil
+	}
+
+	p.query = strings.TrimSpace(text)
+	if p.query == "" {
+		p.ranked = nil
+		p.selectFirst(p.ws)
+		p.si = p.activeIndex(p.ws)
+		p.pushState()
+		return "picker: filter cleared", nil
+	}
+
+	p.ranked = rankSessions(p.state.GetConfig().Sessions, p.query, p.ws)
+	p.si = 0
+	p.selecting = len(p.ranked) > 0
+	if p.selecting {
+		p.ws = p.ranked[0].WS
+	}
+	p.pushState()
+	return fmt.Sprintf("picker: %d matches for %q", len(p.ranked), p.query), nil
+}
+
 func (p *Picker) selectFirst(ws int) {
 	p.si = 0
 	p.selecting = len(p.cache[ws]) > 0
@@ -209,19 +280,16 @@ func (p *Picker) confirm() (string, error) {
 		return "picker: not open", nil
 	}
 
-	sessions := p.cache[p.ws]
-	if len(sessions) == 0 {
+	name, ws, ok := p.selected()
+	if !ok {
+		query, pickerWS := p.query, p.ws
 		p.mu.Unlock()
-		return "picker: no sessions for ws" + strconv.Itoa(p.ws), nil
-	}
-
-	if !p.selecting {
-		p.si = p.activeIndex(p.ws)
-		p.selecting = true
+		if query != "" {
+			return fmt.Sprintf("picker: no matches for %q", query), nil
+		}
+		return "picker: no sessions for ws" + strconv.Itoa(pickerWS), nil
 	}
-
-	name := sessions[p.si]
-	ws := p.ws
+	p.selecting = true
 
 	p.confirmed = true
 	p.pushState()
@@ -245,6 +313,28 @@ func (p *Picker) confirm() (string, error) {
 	return fmt.Sprintf("picker: confirmed %s on ws%d", name, ws), nil
 }
 
+// selected returns the session under the cursor, falling back to the workspace's active one
+// before the user has started cycling.
+func (p *Picker) selected() (string, int, bool) {
+	if p.query != "" {
+		if len(p.ranked) == 0 {
+			return "", 0, false
+		}
+		m := p.ranked[p.si]
+		return m.Name, m.WS, true
+	}
+	sessions := p.cache[p.ws]
+	if len(sessions) == 0 {
+		return "", 0, false
+	}
+	si := p.si
+	if !p.selecting {
+		si = p.activeIndex(p.ws)
+		p.si = si
+	}
+	return sessions[si], p.ws, true
+}
+
 func (p *Picker) activeIndex(ws int) int {
 	active := p.activeSession(ws)
 	for i, name := range p.cache[ws] {
@@ -256,15 +346,30 @@ func (p *Picker) activeIndex(ws int) int {
 }
 
 func (p *Picker) pushState() {
-	sessions := p.cache[p.ws]
-	active := p.activeSession(p.ws)
-
-	items := make([]pickerSession, len(sessions))
-	for i, name := range sessions {
-		items[i] = pickerSession{
-			Name:     name,
-			Selected: p.selecting && i == p.si,
-			Active:   name == active,
+	var items []pickerSession
+	if p.query != "" {
+		items = make([]pickerSession, len(p.ranked))
+		for i, m := range p.ranked {
+			items[i] = pickerSession{
+				Name:     m.Name,
+				Selected: i == p.si,
+				Active:   m.Name == p.activeSession(m.WS),
+				WS:       m.WS,
+				Rank:     i + 1,
+				Score:    m.Score,
+				Matched:  m.Matched,
+			}
+		}
+	} else {
+		sessions := p.cache[p.ws]
+		active := p.activeSession(p.ws)
+		items = make([]pickerSession, len(sessions))
+		for i, name := range sessions {
+			items[i] = pickerSession{
+				Name:     name,
+				Selected: p.selecting && i == p.si,
+				Active:   name == active,
+			}
 		}
 	}
 
@@ -280,12 +385,60 @@ func (p *Picker) pushState() {
 		Sessions:  items,
 		Count:     len(items),
 		Confirmed: p.confirmed,
+		Query:     p.query,
+	}
+	if p.selecting {
+		name, ws := "", p.ws
+		if p.query != "" && len(p.ranked) > 0 {
+			name, ws = p.ranked[p.si].Name, p.ranked[p.si].WS
+		} else if sessions := p.cache[p.ws]; p.query == "" && p.si < len(sessions) {
+			name = sessions[p.si]
+		}
+		if name != "" {
+			payload.Preview = p.preview(name, ws)
+		}
 	}
 
 	data, _ := json.Marshal(payload)
 	exec.Command("eww", "update", fmt.Sprintf("picker-data=%s", data)).Run()
 }
 
+// preview summarizes the roles, tab profiles, and browser snapshot a session will spawn.
+func (p *Picker) preview(name string, ws int) *pickerPreview {
+	s, ok := p.state.GetConfig().Sessions[name]
+	if !ok {
+		return nil
+	}
+	preview := &pickerPreview{Name: name, WS: ws, Project: s.Project, Roles: s.Body, Command: s.Command}
+	if len(preview.Roles) == 0 && s.Command != "" {
+		preview.Roles = []string{"command"}
+	}
+	for _, role := range s.Body {
+		if profile := s.Tabs[role]; profile != "" {
+			preview.Tabs = append(preview.Tabs, role+":"+profile)
+		}
+	}
+	if s.Browser.Snapshot != "" {
+		preview.Browser = s.Browser.Snapshot
+		if count, err := browser.SnapshotTabCount(s.Browser.Snapshot); err == nil {
+			preview.BrowserTabs = count
+		}
+	}
+
+	parts := slices.Clone(preview.Roles)
+	if len(preview.Tabs) > 0 {
+		parts = append(parts, "tabs "+strings.Join(preview.Tabs, " "))
+	}
+	if preview.Browser != "" {
+		parts = append(parts, fmt.Sprintf("%s (%d tabs)", preview.Browser, preview.BrowserTabs))
+	}
+	if preview.Project != "" {
+		parts = append(parts, preview.Project)
+	}
+	preview.Summary = strings.Join(parts, " · ")
+	return preview
+}
+
 func (p *Picker) activeSession(ws int) string {
 	if slices.Contains(p.state.GetOccupied(), ws) {
 		return p.state.GetActiveSession(ws)
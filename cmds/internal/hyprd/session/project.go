@@ -0,0 +1,250 @@
+package session
+
+// project.go opens registered projects onto free workspaces and reports their git status.
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"os/exec"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"dotfiles/cmds/internal/config"
+	"dotfiles/cmds/internal/hyprd/state"
+	"dotfiles/cmds/internal/hyprd/windows"
+)
+
+const projectOpenUsage = "usage: project open <name> [--workspace N] [--var key=value]..."
+
+// Workspaces searched for a free slot, matching the range `hyprd ws up/down` moves across.
+const (
+	minProjectWorkspace = 2
+	maxProjectWorkspace = 5
+)
+
+// ProjectStatus is one registry entry as reported by `project list`.
+type ProjectStatus struct {
+	Name      string `json:"name"`
+	Root      string `json:"root"`
+	Template  string `json:"template,omitempty"`
+	Workspace int    `json:"workspace,omitempty"` // where the project is open; 0 when it isn't
+	Missing   bool   `json:"missing,omitempty"`   // root does not exist
+	Branch    string `json:"branch,omitempty"`    // empty outside a git repo
+	Dirty     bool   `json:"dirty"`
+}
+
+// OpenProject renders a project's session and opens it on --workspace or the first free
+// managed workspace. A project already open is focused instead of opened twice.
+func (l *Layout) OpenProject(args []string) (string, error) {
+	var name string
+	ws := 0
+	vars := make(map[string]string)
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--workspace", "-w", "--var":
+			if i+1 >= len(args) {
+				return "", errors.New(projectOpenUsage)
+			}
+			i++
+			if args[i-1] == "--var" {
+				key, v, ok := strings.Cut(args[i], "=")
+				if !ok || key == "" {
+					return "", fmt.Errorf("invalid --var %q (want key=value)", args[i])
+				}
+				vars[key] = v
+				continue
+			}
+			n, err := strconv.Atoi(args[i])
+			if err != nil || n <= 0 {
+				return "", fmt.Errorf("invalid workspace: %s", args[i])
+			}
+			ws = n
+		default:
+			if name != "" || strings.HasPrefix(args[i], "-") {
+				return "", errors.New(projectOpenUsage)
+			}
+			name = args[i]
+		}
+	}
+	if name == "" {
+		return "", errors.New(projectOpenUsage)
+	}
+
+	cfg := l.state.GetConfig()
+	p, ok := cfg.Projects[name]
+	if !ok {
+		return "", fmt.Errorf("unknown project: %s (use 'project list')", name)
+	}
+	if _, exists := cfg.Sessions[name]; exists && !l.state.IsRendered(name) {
+		return "", fmt.Errorf("project %q clashes with configured session %q; rename one", name, name)
+	}
+	if open := projectWorkspace(l.state, p.Dir()); open != 0 && (ws == 0 || ws == open) {
+		if err := l.hypr.FocusWorkspace(open); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("project %s: already open on ws%d", name, open), nil
+	}
+	if ws == 0 {
+		free, err := l.freeWorkspace()
+		if err != nil {
+			return "", err
+		}
+		ws = free
+	}
+
+	rendered, err := cfg.RenderProject(name, ws, vars)
+	if err != nil {
+		return "", err
+	}
+	if err := validateSessionBrowser(rendered.Session); err != nil {
+		return "", err
+	}
+	l.state.AddRenderedSession(rendered)
+	result, err := l.openSession(rendered.Session)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("project %s on ws%d\n%s", name, ws, result), nil
+}
+
+// freeWorkspace returns the lowest managed workspace without tiled or floating windows;
+// pinned and ignored windows (which openSession leaves alone) don't count.
+func (l *Layout) freeWorkspace() (int, error) {
+	clients, err := l.hypr.Clients()
+	if err != nil {
+		return 0, err
+	}
+	used := make(map[int]bool)
+	for _, c := range clients {
+		if !c.Pinned && !windows.IsIgnored(c.Class) {
+			used[c.Workspace.ID] = true
+		}
+	}
+	for ws := minProjectWorkspace; ws <= maxProjectWorkspace; ws++ {
+		if !used[ws] {
+			return ws, nil
+		}
+	}
+	return 0, fmt.Errorf("no free workspace in %d-%d; pass --workspace", minProjectWorkspace, maxProjectWorkspace)
+}
+
+// projectWorkspace returns the occupied workspace whose project path is dir, or 0.
+func projectWorkspace(s *state.State, dir string) int {
+	for _, ws := range s.GetOccupied() {
+		if s.GetProjectPath(ws) == dir {
+			return ws
+		}
+	}
+	return 0
+}
+
+// ProjectAt returns the name of the registered project rooted at dir, or "".
+func ProjectAt(cfg *config.HyprConfig, dir string) string {
+	for _, name := range slices.Sorted(maps.Keys(cfg.Projects)) {
+		if cfg.Projects[name].Dir() == dir {
+			return name
+		}
+	}
+	return ""
+}
+
+// ProjectStatuses reports every project in name order; git status runs concurrently.
+func ProjectStatuses(cfg *config.HyprConfig, s *state.State) []ProjectStatus {
+	names := slices.Sorted(maps.Keys(cfg.Projects))
+	out := make([]ProjectStatus, len(names))
+	var wg sync.WaitGroup
+	for i, name := range names {
+		p := cfg.Projects[name]
+		out[i] = ProjectStatus{Name: name, Root: p.Dir(), Template: p.Template, Workspace: projectWorkspace(s, p.Dir())}
+		wg.Go(func() {
+			if info, err := os.Stat(out[i].Root); err != nil || !info.IsDir() {
+				out[i].Missing = true
+				return
+			}
+			out[i].Branch, out[i].Dirty = gitStatus(out[i].Root)
+		})
+	}
+	wg.Wait()
+	return out
+}
+
+// gitStatus reads the branch and whether the worktree has changes; branch is "" outside a repo.
+func gitStatus(dir string) (branch string, dirty bool) {
+	out, err := exec.Command("git", "-C", dir, "status", "--porcelain=v1", "--branch").Output()
+	if err != nil {
+		return "", false
+	}
+	lines := strings.Split(strings.TrimRight(string(out), "\n"), "\n")
+	header := strings.TrimPrefix(lines[0], "## ")
+	switch {
+	case strings.HasPrefix(header, "No commits yet on "):
+		branch = strings.TrimPrefix(header, "No commits yet on ")
+	case strings.HasPrefix(header, "HEAD (no branch)"):
+		branch = "HEAD"
+	default:
+		branch, _, _ = strings.Cut(header, "...")
+		branch, _, _ = strings.Cut(branch, " ")
+	}
+	return branch, len(lines) > 1
+}
+
+// FormatProjects renders the registry as one line per project: a * marks open projects and
+// a + dirty worktrees.
+func FormatProjects(list []ProjectStatus) string {
+	if len(list) == 0 {
+		return "no projects (add them under projects: in hyprd.yaml)"
+	}
+	home, _ := os.UserHomeDir()
+	var lines []string
+	for _, p := range list {
+		open := " "
+		where := ""
+		if p.Workspace != 0 {
+			open, where = "*", fmt.Sprintf("  ws%d", p.Workspace)
+		}
+		git := p.Branch
+		switch {
+		case p.Missing:
+			git = "(missing)"
+		case p.Dirty:
+			git += "+"
+		}
+		root := p.Root
+		if home != "" && strings.HasPrefix(root, home+"/") {
+			root = "~" + strings.TrimPrefix(root, home)
+		}
+		lines = append(lines, strings.TrimRight(fmt.Sprintf("%s%-20s %-16s %s%s", open, p.Name, git, root, where), " "))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// CompleteProjects returns project names matching query, best first, for shell completion.
+// An empty query lists every project.
+func CompleteProjects(cfg *config.HyprConfig, query string) []string {
+	names := slices.Sorted(maps.Keys(cfg.Projects))
+	query = strings.ToLower(strings.TrimSpace(query))
+	if query == "" {
+		return names
+	}
+	type match struct {
+		name  string
+		score int
+	}
+	var matches []match
+	for _, name := range names {
+		if score, ok := fuzzyScore(query, name); ok {
+			matches = append(matches, match{name, score})
+		}
+	}
+	slices.SortStableFunc(matches, func(a, b match) int { return cmp.Compare(b.score, a.score) })
+	out := make([]string, len(matches))
+	for i, m := range matches {
+		out[i] = m.name
+	}
+	return out
+}
@@ -0,0 +1,89 @@
+package session
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"dotfiles/cmds/internal/config"
+	"dotfiles/cmds/internal/hyprd/vpn"
+)
+
+const (
+	requiresReachTimeout = 15 * time.Second // a fresh VPN tunnel can take a while to route
+	requiresDialTimeout  = 2 * time.Second
+)
+
+// vpnMu serializes VPN bring-up across sessions checked concurrently, which often share a
+// connection; nmcli races on two activations of the same profile.
+var vpnMu sync.Mutex
+
+// ensureRequires brings up a session's VPNs, then waits for its reach targets and checks its
+// environment. The error names every unmet prerequisite so the session fails with one message.
+func ensureRequires(cfg *config.HyprConfig, s config.Session) error {
+	req := s.Requires
+	if req.IsZero() {
+		return nil
+	}
+
+	var errs []error
+	v := vpn.New(&cfg.VPN)
+	vpnMu.Lock()
+	for _, name := range req.VPN {
+		if _, err := v.Ensure(name); err != nil {
+			errs = append(errs, fmt.Errorf("vpn %s: %w", name, err))
+		}
+	}
+	vpnMu.Unlock()
+	if len(errs) > 0 { // reach targets usually sit behind the VPN; don't wait on them
+		return requiresError(s.Name, errs)
+	}
+
+	for _, target := range unreachable(req.Reach, requiresReachTimeout) {
+		errs = append(errs, fmt.Errorf("%s unreachable after %s", target, requiresReachTimeout))
+	}
+	for _, key := range req.Env {
+		if os.Getenv(key) == "" {
+			errs = append(errs, fmt.Errorf("env %s is not set", key))
+		}
+	}
+	return requiresError(s.Name, errs)
+}
+
+func requiresError(name string, errs []error) error {
+	if len(errs) == 0 {
+		return nil
+	}
+	msgs := make([]string, len(errs))
+	for i, err := range errs {
+		msgs[i] = err.Error()
+	}
+	return fmt.Errorf("session %q requires: %s", name, strings.Join(msgs, "; "))
+}
+
+// unreachable polls each host:port until it accepts a TCP connection or timeout passes,
+// returning the targets that never did.
+func unreachable(targets []string, timeout time.Duration) []string {
+	pending := targets
+	deadline := time.Now().Add(timeout)
+	for len(pending) > 0 {
+		var still []string
+		for _, target := range pending {
+			conn, err := net.DialTimeout("tcp", target, requiresDialTimeout)
+			if err != nil {
+				still = append(still, target)
+				continue
+			}
+			conn.Close()
+		}
+		pending = still
+		if len(pending) == 0 || time.Now().After(deadline) {
+			break
+		}
+		time.Sleep(time.Second)
+	}
+	return pending
+}
@@ -4,18 +4,26 @@ import (
 	"dotfiles/cmds/internal/config"
 	"dotfiles/cmds/internal/hyprd/hypr"
 	"dotfiles/cmds/internal/hyprd/state"
+	"errors"
 	"fmt"
+	"maps"
+	"os"
 	"os/exec"
+	"slices"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
 )
 
-// Share owns screen-share mode: quiet notifications, close widgets, stop GLava, and tighten gaps.
+// Share owns screen-share mode: quiet notifications, close widgets, stop GLava, tighten gaps,
+// and conceal windows matching the share privacy rules on a special workspace.
 type Share struct {
 	hypr    *hypr.Client
 	state   *state.State
 	gapsOut func() config.GapsOutConfig
+	accent  func(color string) error
+	publish func(active bool)
 	mu      sync.Mutex
 }
 
@@ -23,11 +31,31 @@ func NewShare(h *hypr.Client, s *state.State, gapsOut func() config.GapsOutConfi
 	return &Share{hypr: h, state: s, gapsOut: gapsOut}
 }
 
+// SetAccent registers the border override used while sharing; "" clears it.
+func (s *Share) SetAccent(fn func(color string) error) {
+	s.mu.Lock()
+	s.accent = fn
+	s.mu.Unlock()
+}
+
+// SetPublish registers a callback for share mode turning on or off.
+func (s *Share) SetPublish(fn func(active bool)) {
+	s.mu.Lock()
+	s.publish = fn
+	s.mu.Unlock()
+}
+
 // Execute toggles screen-share mode by default, with explicit on/off/status verbs for scripts.
+// `--dry-run` lists the windows the privacy rules would conceal without changing anything.
 func (s *Share) Execute(arg string) (string, error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	fields := strings.Fields(arg)
+	if slices.Contains(fields, "--dry-run") {
+		return s.dryRun()
+	}
+
 	switch strings.TrimSpace(arg) {
 	case "", "toggle":
 		if s.active() {
@@ -47,21 +75,42 @@ func (s *Share) Execute(arg string) (string, error) {
 		}
 		return "share: off", nil
 	default:
-		return "", fmt.Errorf("usage: share [toggle|on|off|status]")
+		return "", fmt.Errorf("usage: share [toggle|on|off|status] [--dry-run]")
 	}
 }
 
 func (s *Share) enter() (string, error) {
+	privacy := s.state.GetConfig().Share.WithDefaults()
+	wasActive := s.active()
 	if err := s.setGaps(s.gaps().Share); err != nil {
 		return "", err
 	}
 	s.state.SetScreenShare(true)
 
 	runCommand("dunstctl", "close-all")
-	runCommand("dunstctl", "set-paused", "true")
+	if privacy.Notify == "mute" {
+		runCommand("dunstctl", "set-paused", "true")
+	}
 	startDetached("ewwd", "close")
 	runCommand("killall", "glava")
 
+	concealed, err := s.concealMatches(privacy)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "hyprd share: %v\n", err)
+	}
+	if privacy.Accent != "" && s.accent != nil {
+		if err := s.accent(privacy.Accent); err != nil {
+			fmt.Fprintf(os.Stderr, "hyprd share: accent: %v\n", err)
+		}
+	}
+
+	if s.publish != nil && !wasActive {
+		s.publish(true)
+	}
+
+	if concealed > 0 {
+		return fmt.Sprintf("share: on (%d concealed)", concealed), nil
+	}
 	return "share: on", nil
 }
 
@@ -70,6 +119,15 @@ func (s *Share) exit() (string, error) {
 		return "", err
 	}
 
+	if err := s.reveal(); err != nil {
+		fmt.Fprintf(os.Stderr, "hyprd share: %v\n", err)
+	}
+	if s.accent != nil {
+		if err := s.accent(""); err != nil {
+			fmt.Fprintf(os.Stderr, "hyprd share: accent: %v\n", err)
+		}
+	}
+
 	startDetached("ewwd", "restore")
 	dispatchGLava(s.hypr)
 	time.AfterFunc(time.Second, func() {
@@ -77,9 +135,100 @@ func (s *Share) exit() (string, error) {
 	})
 
 	s.state.SetScreenShare(false)
+	if s.publish != nil {
+		s.publish(false)
+	}
 	return "share: off", nil
 }
 
+// dryRun lists the windows the privacy rules would conceal, plus any already concealed.
+func (s *Share) dryRun() (string, error) {
+	privacy := s.state.GetConfig().Share.WithDefaults()
+	clients, err := s.hypr.Clients()
+	if err != nil {
+		return "", err
+	}
+	lines := []string{fmt.Sprintf("share: would conceal on special:%s (notify: %s)", privacy.Workspace, privacy.Notify)}
+	for _, c := range concealCandidates(clients, privacy) {
+		lines = append(lines, fmt.Sprintf("  ws%-3d %-20s %s", c.Workspace.ID, c.Class, c.Title))
+	}
+	if len(lines) == 1 {
+		lines = append(lines, "  (no matching windows)")
+	}
+	if concealed := s.state.GetConcealed(); len(concealed) > 0 {
+		lines = append(lines, fmt.Sprintf("already concealed: %d (restored on share off)", len(concealed)))
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// concealMatches moves every matching window to the privacy workspace.
+func (s *Share) concealMatches(privacy config.ShareConfig) (int, error) {
+	clients, err := s.hypr.Clients()
+	if err != nil {
+		return 0, err
+	}
+	var errs []error
+	concealed := 0
+	for _, c := range concealCandidates(clients, privacy) {
+		if err := conceal(s.hypr, s.state, privacy, c); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		concealed++
+	}
+	return concealed, errors.Join(errs...)
+}
+
+// reveal returns concealed windows to their origin workspaces, silently.
+func (s *Share) reveal() error {
+	concealed := s.state.TakeConcealed()
+	var errs []error
+	for _, addr := range slices.Sorted(maps.Keys(concealed)) {
+		if err := s.hypr.MoveWindowToWorkspace(addr, strconv.Itoa(concealed[addr]), false); err != nil {
+			errs = append(errs, fmt.Errorf("reveal %s: %w", addr, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// ConcealOpened applies the share privacy rules to a window mapped while sharing.
+func ConcealOpened(h *hypr.Client, s *state.State, addr string) error {
+	if !s.GetScreenShare() {
+		return nil
+	}
+	privacy := s.GetConfig().Share.WithDefaults()
+	clients, err := h.Clients()
+	if err != nil {
+		return err
+	}
+	for _, c := range concealCandidates(clients, privacy) {
+		if c.Address == addr {
+			return conceal(h, s, privacy, c)
+		}
+	}
+	return nil
+}
+
+// concealCandidates returns matching windows on regular workspaces; special workspaces
+// (negative IDs) are already off screen and have no origin to return to.
+func concealCandidates(clients []hypr.Window, privacy config.ShareConfig) []hypr.Window {
+	var matches []hypr.Window
+	for _, c := range clients {
+		if c.Workspace.ID > 0 && privacy.Conceals(c.Class, c.Title) {
+			matches = append(matches, c)
+		}
+	}
+	return matches
+}
+
+func conceal(h *hypr.Client, s *state.State, privacy config.ShareConfig, w hypr.Window) error {
+	if err := h.MoveWindowToWorkspace(w.Address, "special:"+privacy.Workspace, false); err != nil {
+		return fmt.Errorf("conceal %s: %w", w.Class, err)
+	}
+	s.AddConcealed(w.Address, w.Workspace.ID)
+	return nil
+}
+
 func (s *Share) active() bool {
 	if s.state.GetScreenShare() {
 		return true
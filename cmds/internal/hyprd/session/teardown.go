@@ -0,0 +1,209 @@
+package session
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+
+	"dotfiles/cmds/internal/config"
+	"dotfiles/cmds/internal/hyprd/browser"
+	"dotfiles/cmds/internal/hyprd/hypr"
+	"dotfiles/cmds/internal/hyprd/windows"
+)
+
+const layoutCloseUsage = "usage: layout close [name|--workspace N] [--snapshot]"
+
+// close tears down a session: the inverse of openSession.
+//
+// With --snapshot the session's browser window is saved under its configured snapshot name
+// first, so the next open restores the tabs as they were. Layout state is cleared before any
+// window closes so the closewindow handlers don't pull shadow or monocle windows back in.
+func (l *Layout) close(args []string) (string, error) {
+	var name string
+	ws := 0
+	snapshot := false
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--workspace", "-w":
+			if i+1 >= len(args) {
+				return "", errors.New(layoutCloseUsage)
+			}
+			n, err := strconv.Atoi(args[i+1])
+			if err != nil || n <= 0 {
+				return "", fmt.Errorf("invalid workspace: %s", args[i+1])
+			}
+			ws = n
+			i++
+		case "--snapshot":
+			snapshot = true
+		default:
+			if name != "" || strings.HasPrefix(args[i], "-") {
+				return "", errors.New(layoutCloseUsage)
+			}
+			name = args[i]
+		}
+	}
+	if name != "" && ws != 0 {
+		return "", errors.New(layoutCloseUsage)
+	}
+
+	sessions := l.state.GetConfig().Sessions
+	if name != "" {
+		s, ok := sessions[name]
+		if !ok {
+			return "", fmt.Errorf("unknown session: %s (use 'layout list')", name)
+		}
+		ws = s.Workspace
+		if active := l.state.GetActiveSession(ws); active != name {
+			return "", fmt.Errorf("session %q is not active on ws%d (active: %s)", name, ws, cmp.Or(active, "none"))
+		}
+	}
+	if ws == 0 {
+		active, err := l.hypr.ActiveWorkspace()
+		if err != nil {
+			return "", err
+		}
+		ws = active
+	}
+	if name == "" {
+		name = l.state.GetActiveSession(ws)
+	}
+	s, ok := sessions[name]
+	if !ok {
+		return "", fmt.Errorf("no active session on ws%d", ws)
+	}
+
+	targets, err := l.sessionWindows(s)
+	if err != nil {
+		return "", err
+	}
+
+	var notes []string
+	if snapshot {
+		note, err := l.snapshotOnClose(s, targets)
+		if err != nil {
+			return "", fmt.Errorf("snapshot browser (nothing closed): %w", err)
+		}
+		notes = append(notes, note)
+	}
+
+	l.state.ClearThreeBody(ws)
+	l.state.ClearMonocle(ws)
+
+	closed := make(map[string]int)
+	var failed []string
+	for _, w := range targets {
+		if err := l.hypr.CloseWindow(w.Address); err != nil {
+			failed = append(failed, fmt.Sprintf("%s (%v)", w.Class, err))
+			continue
+		}
+		closed[strings.ToLower(w.Class)]++
+	}
+
+	l.state.ClearActiveSession(ws)
+	l.state.SetProjectPath(ws, "")
+
+	result := fmt.Sprintf("closed session: %s on ws%d (%s)", s.Name, ws, closedSummary(closed))
+	if len(failed) > 0 {
+		notes = append(notes, "failed to close "+strings.Join(failed, ", "))
+	}
+	for _, note := range notes {
+		result += "\n" + note
+	}
+	return result, nil
+}
+
+// sessionWindows returns the kitty, Firefox, and role windows a session owns: those tiled on its
+// workspace plus the three-body shadow and monocle-displaced windows parked elsewhere.
+func (l *Layout) sessionWindows(s config.Session) ([]hypr.Window, error) {
+	clients, err := l.hypr.Clients()
+	if err != nil {
+		return nil, err
+	}
+
+	parked := make(map[string]bool)
+	if tb := l.state.GetThreeBody(s.Workspace); tb != nil {
+		parked[tb.Master] = true
+		parked[tb.Active] = true
+		parked[tb.Shadow] = true
+	}
+	if ms := l.state.GetMonocle(s.Workspace); ms != nil {
+		parked[ms.Focused] = true
+		for _, mw := range ms.Windows {
+			parked[mw.Address] = true
+		}
+	}
+
+	roles := slices.Clone(s.Body)
+	if s.Command != "" {
+		roles = append(roles, s.Name)
+	}
+
+	var targets []hypr.Window
+	for _, c := range clients {
+		if c.Pinned || windows.IsIgnored(c.Class) {
+			continue
+		}
+		if !parked[c.Address] && c.Workspace.ID != s.Workspace {
+			continue
+		}
+		if sessionFirefoxWindow(c) || strings.EqualFold(c.Class, "kitty") || slices.ContainsFunc(roles, func(role string) bool {
+			return l.matchesRole(s, &c, role)
+		}) {
+			targets = append(targets, c)
+		}
+	}
+	return targets, nil
+}
+
+// snapshotOnClose saves the session's browser window under its configured snapshot name.
+//
+// A session without a snapshot or open browser only gets a note; a failed write is an error
+// so the caller can keep the window open rather than lose its tabs.
+func (l *Layout) snapshotOnClose(s config.Session, targets []hypr.Window) (string, error) {
+	if s.Browser.Snapshot == "" {
+		return "snapshot: session has no browser snapshot configured", nil
+	}
+	var browserWindow *hypr.Window
+	for i, w := range targets {
+		if !sessionFirefoxWindow(w) {
+			continue
+		}
+		if browserWindow == nil || browser.SnapshotMatchesWindowTitle(s.Browser.Snapshot, w.Title) {
+			browserWindow = &targets[i]
+		}
+	}
+	if browserWindow == nil {
+		return "snapshot: no browser window open", nil
+	}
+	if l.hypr.Skip("snapshot", "save browser window as "+s.Browser.Snapshot) {
+		return "snapshot: " + s.Browser.Snapshot + " (dry run)", nil
+	}
+	dir, err := browser.NewBrowser(l.hypr, l.state).SnapshotWindow(s.Browser.Snapshot, *browserWindow)
+	if err != nil {
+		return "", err
+	}
+	return "snapshot: " + dir, nil
+}
+
+// closedSummary renders per-class close counts, e.g. "3 windows: firefox, kitty×2".
+func closedSummary(closed map[string]int) string {
+	if len(closed) == 0 {
+		return "no windows"
+	}
+	total := 0
+	var parts []string
+	for _, class := range slices.Sorted(maps.Keys(closed)) {
+		total += closed[class]
+		if closed[class] > 1 {
+			parts = append(parts, fmt.Sprintf("%s×%d", class, closed[class]))
+		} else {
+			parts = append(parts, class)
+		}
+	}
+	return fmt.Sprintf("%d windows: %s", total, strings.Join(parts, ", "))
+}
@@ -0,0 +1,152 @@
+package session
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"dotfiles/cmds/internal/config"
+
+	"gopkg.in/yaml.v3"
+)
+
+const layoutNewUsage = "usage: layout new <name> --template <name> [--var key=value]... [--workspace N] [--save] [--no-open]"
+
+// templateSession mirrors config.Session's YAML shape with empty fields omitted, for drop-ins.
+type templateSession struct {
+	Project  string                 `yaml:"project,omitempty"`
+	Body     []string               `yaml:"body,omitempty"`
+	Layout   config.SessionLayout   `yaml:"layout,omitempty"`
+	Browser  config.BrowserConfig   `yaml:"browser,omitempty"`
+	Tabs     map[string]string      `yaml:"tabs,omitempty"`
+	Command  string                 `yaml:"command,omitempty"`
+	Class    string                 `yaml:"class,omitempty"`
+	Monocle  bool                   `yaml:"monocle,omitempty"`
+	Requires config.SessionRequires `yaml:"requires,omitempty"`
+}
+
+// newFromTemplate renders a session template, registers the result, and opens it.
+//
+// --save also writes the rendered block (and any cloned tab profiles) to a drop-in for review;
+// --no-open only registers it, e.g. to pick it later from the session picker.
+func (l *Layout) newFromTemplate(args []string) (string, error) {
+	var name, tmpl string
+	ws := 0
+	vars := make(map[string]string)
+	save, open := false, true
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "--template", "-t", "--var", "--workspace", "-w":
+			if i+1 >= len(args) {
+				return "", errors.New(layoutNewUsage)
+			}
+			value := args[i+1]
+			i++
+			switch args[i-1] {
+			case "--template", "-t":
+				tmpl = value
+			case "--var":
+				key, v, ok := strings.Cut(value, "=")
+				if !ok || key == "" {
+					return "", fmt.Errorf("invalid --var %q (want key=value)", value)
+				}
+				vars[key] = v
+			default:
+				n, err := strconv.Atoi(value)
+				if err != nil || n <= 0 {
+					return "", fmt.Errorf("invalid workspace: %s", value)
+				}
+				ws = n
+			}
+		case "--save":
+			save = true
+		case "--no-open":
+			open = false
+		default:
+			if name != "" || strings.HasPrefix(args[i], "-") {
+				return "", errors.New(layoutNewUsage)
+			}
+			name = args[i]
+		}
+	}
+	if name == "" || tmpl == "" {
+		return "", errors.New(layoutNewUsage)
+	}
+	if slices.Contains(config.LayoutVerbs, name) {
+		return "", fmt.Errorf("session name %q is a layout subcommand", name)
+	}
+
+	rendered, err := l.state.GetConfig().RenderTemplate(tmpl, name, ws, vars)
+	if err != nil {
+		return "", err
+	}
+	if err := validateSessionBrowser(rendered.Session); err != nil {
+		return "", err
+	}
+	l.state.AddRenderedSession(rendered)
+
+	lines := []string{fmt.Sprintf("rendered session: %s on ws%d from template %s", name, rendered.Session.Workspace, tmpl)}
+	if save {
+		path, err := saveRenderedSession(rendered)
+		if err != nil {
+			return "", err
+		}
+		lines = append(lines, "saved: "+path)
+	}
+	if open {
+		result, err := l.openSession(rendered.Session)
+		if err != nil {
+			return "", err
+		}
+		lines = append(lines, result)
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// saveRenderedSession writes a rendered session to config/hyprd.d/<name>.yaml.
+func saveRenderedSession(r config.RenderedSession) (string, error) {
+	s := r.Session
+	block, err := marshalSessionBlock(s.Workspace, s.Name, templateSession{
+		Project:  s.Project,
+		Body:     s.Body,
+		Layout:   s.Layout,
+		Browser:  s.Browser,
+		Tabs:     s.Tabs,
+		Command:  s.Command,
+		Class:    s.Class,
+		Monocle:  s.Monocle,
+		Requires: s.Requires,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	var out bytes.Buffer
+	fmt.Fprintf(&out, "# rendered by `hyprd layout new %s`; review, then merge into hyprd.yaml.\n", s.Name)
+	out.WriteString("sessions:\n" + indentBlock(block, "  "))
+	if len(r.Tabs) > 0 {
+		tabs, err := yaml.Marshal(r.Tabs)
+		if err != nil {
+			return "", err
+		}
+		out.WriteString("tabs:\n" + indentBlock(string(tabs), "  "))
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	path := filepath.Join(home, config.DropInPath("hyprd", s.Name))
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(path, out.Bytes(), 0o644); err != nil {
+		return "", err
+	}
+	return path, nil
+}
@@ -0,0 +1,27 @@
+package state
+
+// BackgroundState records the wallpaper hyprd last selected and whether the user pinned it.
+//
+// A pin holds until the schedule picks something other than Scheduled, the pick current when pinned.
+type BackgroundState struct {
+	File      string `json:"file"`
+	Pinned    bool   `json:"pinned,omitempty"`
+	Scheduled string `json:"scheduled,omitempty"`
+}
+
+// GetBackground returns a copy of the wallpaper selection, or nil before the first selection.
+func (s *State) GetBackground() *BackgroundState {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.Background == nil {
+		return nil
+	}
+	copy := *s.Background
+	return &copy
+}
+
+func (s *State) SetBackground(b *BackgroundState) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.Background = b
+}
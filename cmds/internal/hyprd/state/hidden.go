@@ -1,10 +1,14 @@
 package state
 
 // HiddenState records a window stashed on the special workspace, with enough context to restore its layout position.
+//
+// SwallowedBy is set when a terminal is hidden because a GUI child it launched took its tile.
 type HiddenState struct {
-	Address    string `json:"address"`
-	OriginWS   int    `json:"origin_ws"`
-	SlaveIndex int    `json:"slave_index"`
+	Address     string `json:"address"`
+	OriginWS    int    `json:"origin_ws"`
+	SlaveIndex  int    `json:"slave_index"`
+	WasMaster   bool   `json:"was_master,omitempty"`
+	SwallowedBy string `json:"swallowed_by,omitempty"`
 }
 
 // ThreeBodyState is a three-window layout: Master is always visible, exactly one of Active/Shadow is rendered.
@@ -28,6 +32,16 @@ type MonocleState struct {
 	SavedSplitRatio string          `json:"saved_split_ratio,omitempty"`
 }
 
+// PiPState records the single picture-in-picture window and the tile slot it left behind.
+type PiPState struct {
+	Address     string `json:"address"`
+	OriginWS    int    `json:"origin_ws"`
+	Corner      string `json:"corner"`
+	WasFloating bool   `json:"was_floating"`
+	WasMaster   bool   `json:"was_master"`
+	SlaveIndex  int    `json:"slave_index"`
+}
+
 // GetHidden returns a deep copy of the hidden-window map.
 func (s *State) GetHidden() map[string]*HiddenState {
 	s.mu.RLock()
@@ -60,3 +74,16 @@ func (s *State) IsHidden(addr string) bool {
 	_, ok := s.Hidden[addr]
 	return ok
 }
+
+// TakeSwallowed removes and returns the terminal hidden for child, or nil.
+func (s *State) TakeSwallowed(child string) *HiddenState {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for addr, h := range s.Hidden {
+		if h.SwallowedBy == child {
+			delete(s.Hidden, addr)
+			return h
+		}
+	}
+	return nil
+}
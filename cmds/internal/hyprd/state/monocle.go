@@ -40,7 +40,7 @@ func (s *State) AllMonocle() map[int]*MonocleState {
 	return out
 }
 
-// ClearWindowState purges all traces of addr from Hidden, DisplacedMasters, ThreeBody, and Monocle on window-close.
+// ClearWindowState purges all traces of addr from Hidden, Concealed, DisplacedMasters, PiP, ThreeBody, and Monocle on window-close.
 //
 // Returns the removed ThreeBodyState so the caller can restore the surviving pair, or nil if none matched.
 func (s *State) ClearWindowState(addr string) *ThreeBodyState {
@@ -48,6 +48,10 @@ func (s *State) ClearWindowState(addr string) *ThreeBodyState {
 	defer s.mu.Unlock()
 
 	delete(s.Hidden, addr)
+	delete(s.Concealed, addr)
+	if s.PiP != nil && s.PiP.Address == addr {
+		s.PiP = nil
+	}
 	for ws, a := range s.DisplacedMasters {
 		if a == addr {
 			delete(s.DisplacedMasters, ws)
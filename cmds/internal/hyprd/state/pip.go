@@ -0,0 +1,24 @@
+package state
+
+// GetPiP returns a copy of the picture-in-picture state, or nil if no window is in pip.
+func (s *State) GetPiP() *PiPState {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.PiP == nil {
+		return nil
+	}
+	copy := *s.PiP
+	return &copy
+}
+
+func (s *State) SetPiP(p *PiPState) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.PiP = p
+}
+
+func (s *State) ClearPiP() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.PiP = nil
+}
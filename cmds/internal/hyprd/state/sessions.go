@@ -1,6 +1,11 @@
 package state
 
-import "dotfiles/cmds/internal/config"
+import (
+	"maps"
+	"reflect"
+
+	"dotfiles/cmds/internal/config"
+)
 
 func (s *State) GetProjectPath(ws int) string {
 	s.mu.RLock()
@@ -35,6 +40,13 @@ func (s *State) SetActiveSession(ws int, name string) {
 	s.ActiveSessions[ws] = name
 }
 
+// ClearActiveSession drops the workspace's session choice, falling back to the configured default.
+func (s *State) ClearActiveSession(ws int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.ActiveSessions, ws)
+}
+
 // ActiveSession resolves the workspace's current session name to its config entry.
 func (s *State) ActiveSession(ws int) (config.Session, bool) {
 	name := s.GetActiveSession(ws)
@@ -55,3 +67,58 @@ func (s *State) SessionTabProfile(ws int, body string) string {
 	}
 	return session.Tabs[body]
 }
+
+// AddRenderedSession registers a template-rendered session and its cloned tab profiles.
+//
+// Rendered sessions live in state rather than hyprd.yaml, so they survive config reloads and
+// hot restarts but not a cold start; save them to a drop-in to keep them.
+//
+// Re-rendering a name, as reopening a project does, replaces the earlier render.
+func (s *State) AddRenderedSession(r config.RenderedSession) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	name := r.Session.Name
+	if prev, ok := s.Rendered[name]; ok && s.config != nil && reflect.DeepEqual(s.config.Sessions[name], prev.Session) {
+		merged := *s.config
+		merged.Sessions = maps.Clone(s.config.Sessions)
+		delete(merged.Sessions, name)
+		s.config = &merged
+	}
+	s.Rendered[name] = r
+	s.config = withRendered(s.config, map[string]config.RenderedSession{name: r})
+}
+
+// IsRendered reports whether name was registered by AddRenderedSession rather than configured.
+func (s *State) IsRendered(name string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	_, ok := s.Rendered[name]
+	return ok
+}
+
+// withRendered returns a copy of cfg with rendered sessions and tab profiles merged in;
+// configured sessions win on name clashes. cfg itself is shared and never mutated.
+func withRendered(cfg *config.HyprConfig, rendered map[string]config.RenderedSession) *config.HyprConfig {
+	if cfg == nil || len(rendered) == 0 {
+		return cfg
+	}
+	merged := *cfg
+	merged.Sessions = maps.Clone(cfg.Sessions)
+	merged.Tabs = maps.Clone(cfg.Tabs)
+	if merged.Sessions == nil {
+		merged.Sessions = make(config.SessionsConfig)
+	}
+	if merged.Tabs == nil {
+		merged.Tabs = make(map[string]config.TabProfile)
+	}
+	for name, r := range rendered {
+		if _, exists := merged.Sessions[name]; exists {
+			continue
+		}
+		merged.Sessions[name] = r.Session
+		for profile, tabs := range r.Tabs {
+			merged.Tabs[profile] = tabs
+		}
+	}
+	return &merged
+}
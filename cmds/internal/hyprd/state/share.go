@@ -0,0 +1,25 @@
+package state
+
+import "maps"
+
+// GetConcealed returns a copy of the windows share mode moved to the privacy workspace (address -> origin workspace).
+func (s *State) GetConcealed() map[string]int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return maps.Clone(s.Concealed)
+}
+
+func (s *State) AddConcealed(addr string, originWS int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.Concealed[addr] = originWS
+}
+
+// TakeConcealed returns and clears every concealed window, for restore on share off.
+func (s *State) TakeConcealed() map[string]int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	concealed := s.Concealed
+	s.Concealed = make(map[string]int)
+	return concealed
+}
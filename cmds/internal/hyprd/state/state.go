@@ -16,17 +16,22 @@ import (
 type State struct {
 	mu sync.RWMutex
 
-	Workspace          int                     `json:"workspace"`
-	OccupiedWorkspaces []int                   `json:"occupied_workspaces"`
-	Hidden             map[string]*HiddenState `json:"hidden,omitempty"`
-	DisplacedMasters   map[int]string          `json:"displaced_masters,omitempty"`
-	ThreeBody          map[int]*ThreeBodyState `json:"three_body,omitempty"`
-	ProjectPaths       map[int]string          `json:"project_paths,omitempty"`
-	Monocle            map[int]*MonocleState   `json:"monocle,omitempty"`
-	SplitRatio         string                  `json:"split_ratio"`
-	ActiveSessions     map[int]string          `json:"active_sessions,omitempty"`
-	ScreenShare        bool                    `json:"screen_share"`
-	pendingLaunches    map[string]time.Time    `json:"-"`
+	Workspace          int                               `json:"workspace"`
+	OccupiedWorkspaces []int                             `json:"occupied_workspaces"`
+	Hidden             map[string]*HiddenState           `json:"hidden,omitempty"`
+	DisplacedMasters   map[int]string                    `json:"displaced_masters,omitempty"`
+	ThreeBody          map[int]*ThreeBodyState           `json:"three_body,omitempty"`
+	ProjectPaths       map[int]string                    `json:"project_paths,omitempty"`
+	Monocle            map[int]*MonocleState             `json:"monocle,omitempty"`
+	PiP                *PiPState                         `json:"pip,omitempty"`
+	Background         *BackgroundState                  `json:"background,omitempty"`
+	SplitRatio         string                            `json:"split_ratio"`
+	ActiveSessions     map[int]string                    `json:"active_sessions,omitempty"`
+	ScreenShare        bool                              `json:"screen_share"`
+	BootProfile        string                            `json:"boot_profile,omitempty"`
+	Concealed          map[string]int                    `json:"concealed,omitempty"`
+	Rendered           map[string]config.RenderedSession `json:"rendered,omitempty"`
+	pendingLaunches    map[string]time.Time              `json:"-"`
 	config             *config.HyprConfig
 }
 
@@ -40,6 +45,8 @@ func NewState(cfg *config.HyprConfig) *State {
 		ProjectPaths:       make(map[int]string),
 		Monocle:            make(map[int]*MonocleState),
 		ActiveSessions:     make(map[int]string),
+		Concealed:          make(map[string]int),
+		Rendered:           make(map[string]config.RenderedSession),
 		pendingLaunches:    make(map[string]time.Time),
 		SplitRatio:         "default",
 		config:             cfg,
@@ -101,6 +108,19 @@ func (s *State) GetScreenShare() bool {
 	return s.ScreenShare
 }
 
+// SetBootProfile records the boot profile the last init ran with; "" means none.
+func (s *State) SetBootProfile(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.BootProfile = name
+}
+
+func (s *State) GetBootProfile() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.BootProfile
+}
+
 func (s *State) GetConfig() *config.HyprConfig {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
@@ -121,6 +141,9 @@ func (s *State) Restore(data []byte) error {
 	s.OccupiedWorkspaces = snap.OccupiedWorkspaces
 	s.SplitRatio = snap.SplitRatio
 	s.ScreenShare = snap.ScreenShare
+	s.BootProfile = snap.BootProfile
+	s.PiP = snap.PiP
+	s.Background = snap.Background
 
 	if snap.Hidden != nil {
 		s.Hidden = snap.Hidden
@@ -140,6 +163,13 @@ func (s *State) Restore(data []byte) error {
 	if snap.ActiveSessions != nil {
 		s.ActiveSessions = snap.ActiveSessions
 	}
+	if snap.Concealed != nil {
+		s.Concealed = snap.Concealed
+	}
+	if snap.Rendered != nil {
+		s.Rendered = snap.Rendered
+		s.config = withRendered(s.config, s.Rendered)
+	}
 	if s.pendingLaunches == nil {
 		s.pendingLaunches = make(map[string]time.Time)
 	}
@@ -147,9 +177,9 @@ func (s *State) Restore(data []byte) error {
 	return nil
 }
 
-// ReloadConfig swaps in a new HyprConfig during hot-reload.
+// ReloadConfig swaps in a new HyprConfig during hot-reload, keeping template-rendered sessions.
 func (s *State) ReloadConfig(cfg *config.HyprConfig) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
-	s.config = cfg
+	s.config = withRendered(cfg, s.Rendered)
 }
@@ -194,6 +194,30 @@ func (v *VPN) up(conn connection) (string, error) {
 	return fmt.Sprintf("vpn connected: %s", conn.Name), nil
 }
 
+// Ensure brings up a configured connection unless it is already active.
+func (v *VPN) Ensure(name string) (string, error) {
+	conn, err := v.resolveConfigured(name)
+	if err != nil {
+		return "", err
+	}
+	active, err := v.active(conn.Name)
+	if err != nil {
+		return "", err
+	}
+	if active {
+		return v.status(conn)
+	}
+	if _, err := v.up(conn); err != nil {
+		return "", err
+	}
+	if active, err = v.active(conn.Name); err != nil {
+		return "", err
+	} else if !active {
+		return "", fmt.Errorf("vpn %s did not come up", conn.Name)
+	}
+	return fmt.Sprintf("vpn connected: %s", conn.Name), nil
+}
+
 func (v *VPN) down(conn connection) (string, error) {
 	if err := runNMCLI("connection", "down", conn.Name); err != nil {
 		return "", err
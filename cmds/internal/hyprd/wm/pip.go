@@ -0,0 +1,263 @@
+package wm
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"dotfiles/cmds/internal/config"
+	"dotfiles/cmds/internal/hyprd/hypr"
+	"dotfiles/cmds/internal/hyprd/state"
+	"dotfiles/cmds/internal/hyprd/windows"
+)
+
+// PiP floats and pins one window at a small size in a monitor corner, inset by the active gaps_out.
+//
+// The origin workspace and tile slot are kept in state so toggling off re-tiles the window where it was.
+// Refused on workspaces running three-body or monocle, which already own the tile order.
+type PiP struct {
+	hypr  *hypr.Client
+	state *state.State
+}
+
+func NewPiP(h *hypr.Client, s *state.State) *PiP {
+	return &PiP{hypr: h, state: s}
+}
+
+// Execute dispatches toggle|corner <nw|ne|se|sw>|cycle|avoid; empty defaults to toggle.
+func (p *PiP) Execute(arg string) (string, error) {
+	fields := strings.Fields(arg)
+	if len(fields) == 0 {
+		return p.toggle()
+	}
+
+	switch fields[0] {
+	case "toggle":
+		return p.toggle()
+	case "corner":
+		if len(fields) < 2 || !slices.Contains(config.PiPCorners, fields[1]) {
+			return "", fmt.Errorf("usage: pip corner <nw|ne|se|sw>")
+		}
+		return p.corner(fields[1])
+	case "cycle":
+		return p.cycle()
+	case "avoid":
+		return p.avoid()
+	default:
+		return "", fmt.Errorf("usage: pip [toggle|corner <nw|ne|se|sw>|cycle|avoid]")
+	}
+}
+
+func (p *PiP) toggle() (string, error) {
+	if ps := p.state.GetPiP(); ps != nil {
+		return p.exit(ps)
+	}
+
+	win, err := p.hypr.ActiveWindow()
+	if err != nil {
+		return "", fmt.Errorf("get active window: %w", err)
+	}
+	if win == nil {
+		return "no active window", nil
+	}
+	return p.enter(win)
+}
+
+// enter records the window's tile slot, then floats, pins, resizes, and places it in the configured corner.
+func (p *PiP) enter(win *hypr.Window) (string, error) {
+	wsID := win.Workspace.ID
+	if p.state.GetThreeBody(wsID) != nil {
+		return "three-body active: pip unavailable", nil
+	}
+	if p.state.GetMonocle(wsID) != nil {
+		return "monocle active: pip unavailable", nil
+	}
+
+	ps := &state.PiPState{
+		Address:     win.Address,
+		OriginWS:    wsID,
+		Corner:      p.config().Corner,
+		WasFloating: win.Floating,
+	}
+	if !win.Floating {
+		tiled, err := windows.GetTiledWindows(p.hypr, wsID)
+		if err != nil {
+			return "", err
+		}
+		ps.WasMaster = windows.IsMaster(tiled, win.Address)
+		ps.SlaveIndex = max(windows.SlaveIndex(windows.GetSlaves(tiled), win.Address), 0)
+
+		if err := p.hypr.ToggleFloatActive(); err != nil {
+			return "", fmt.Errorf("pip float: %w", err)
+		}
+	}
+
+	cfg := p.config()
+	if err := p.hypr.ResizeActiveExact(cfg.Width, cfg.Height); err != nil {
+		return "", fmt.Errorf("pip resize: %w", err)
+	}
+	if !win.Pinned {
+		if err := p.hypr.TogglePinActive(); err != nil {
+			return "", fmt.Errorf("pip pin: %w", err)
+		}
+	}
+
+	p.state.SetPiP(ps)
+	if err := p.place(ps.Address, ps.Corner); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("pip: %s %dx%d %s", win.Address, cfg.Width, cfg.Height, ps.Corner), nil
+}
+
+// exit unpins the window, returns it to its origin workspace, and walks it back into its saved tile slot.
+func (p *PiP) exit(ps *state.PiPState) (string, error) {
+	win, err := p.window(ps.Address)
+	if err != nil {
+		return "", err
+	}
+	if win == nil {
+		p.state.ClearPiP()
+		return "pip off: window gone", nil
+	}
+
+	if err := p.hypr.FocusWindow(ps.Address); err != nil {
+		return "", fmt.Errorf("focus pip window: %w", err)
+	}
+	if win.Pinned {
+		if err := p.hypr.TogglePinActive(); err != nil {
+			return "", fmt.Errorf("pip unpin: %w", err)
+		}
+	}
+	if err := p.hypr.MoveWindowToWorkspace(ps.Address, strconv.Itoa(ps.OriginWS), true); err != nil {
+		return "", fmt.Errorf("pip return to ws%d: %w", ps.OriginWS, err)
+	}
+	// Cleared only once the window is unpinned and home, so a failed exit can be retried.
+	p.state.ClearPiP()
+	if ps.WasFloating {
+		return fmt.Sprintf("pip off: %s floating on ws%d", ps.Address, ps.OriginWS), nil
+	}
+
+	if err := p.hypr.ToggleFloatActive(); err != nil {
+		return "", fmt.Errorf("pip tile: %w", err)
+	}
+	if ps.WasMaster {
+		_ = p.hypr.LayoutMsg("swapwithmaster master")
+	} else {
+		NewHide(p.hypr, p.state).restoreSlavePosition(ps.OriginWS, ps.SlaveIndex)
+	}
+	return fmt.Sprintf("pip off: %s tiled on ws%d", ps.Address, ps.OriginWS), nil
+}
+
+func (p *PiP) corner(corner string) (string, error) {
+	ps := p.state.GetPiP()
+	if ps == nil {
+		return "pip inactive", nil
+	}
+	if err := p.place(ps.Address, corner); err != nil {
+		return "", err
+	}
+	ps.Corner = corner
+	p.state.SetPiP(ps)
+	return "pip: " + corner, nil
+}
+
+// cycle moves the pip window to the next corner clockwise.
+func (p *PiP) cycle() (string, error) {
+	ps := p.state.GetPiP()
+	if ps == nil {
+		return "pip inactive", nil
+	}
+	i := slices.Index(config.PiPCorners, ps.Corner)
+	return p.corner(config.PiPCorners[(i+1)%len(config.PiPCorners)])
+}
+
+// avoid flips the pip window to the horizontally opposite corner when the cursor is in its quadrant.
+func (p *PiP) avoid() (string, error) {
+	ps := p.state.GetPiP()
+	if ps == nil {
+		return "pip inactive", nil
+	}
+	mon, err := p.hypr.FocusedMonitor()
+	if err != nil {
+		return "", fmt.Errorf("get monitor: %w", err)
+	}
+	if mon == nil {
+		return "", fmt.Errorf("no monitor")
+	}
+	x, y, err := p.hypr.CursorPos()
+	if err != nil {
+		return "", fmt.Errorf("get cursor: %w", err)
+	}
+
+	w, h := mon.LogicalSize()
+	vertical, horizontal := "n", "w"
+	if y >= mon.Y+h/2 {
+		vertical = "s"
+	}
+	if x >= mon.X+w/2 {
+		horizontal = "e"
+	}
+	if vertical+horizontal != ps.Corner {
+		return "pip: cursor clear", nil
+	}
+
+	flipped := map[string]string{"nw": "ne", "ne": "nw", "se": "sw", "sw": "se"}
+	return p.corner(flipped[ps.Corner])
+}
+
+// place moves addr to corner on the focused monitor, inside reserved areas and the active gaps_out.
+func (p *PiP) place(addr, corner string) error {
+	mon, err := p.hypr.FocusedMonitor()
+	if err != nil {
+		return fmt.Errorf("get monitor: %w", err)
+	}
+	if mon == nil {
+		return fmt.Errorf("no monitor")
+	}
+
+	cfg := p.config()
+	gaps := p.gaps()
+	top, right, bottom, left := gaps.Edges()
+	w, h := mon.LogicalSize()
+
+	x := mon.X + mon.Reserved[0] + left + cfg.Margin
+	if strings.HasSuffix(corner, "e") {
+		x = mon.X + w - mon.Reserved[2] - right - cfg.Margin - cfg.Width
+	}
+	y := mon.Y + mon.Reserved[1] + top + cfg.Margin
+	if strings.HasPrefix(corner, "s") {
+		y = mon.Y + h - mon.Reserved[3] - bottom - cfg.Margin - cfg.Height
+	}
+
+	if err := p.hypr.MoveWindowExact(addr, x, y); err != nil {
+		return fmt.Errorf("pip move %s: %w", corner, err)
+	}
+	return nil
+}
+
+// gaps returns the outer gaps currently applied: share gaps while screen sharing, normal otherwise.
+func (p *PiP) gaps() config.OuterGaps {
+	gaps := p.state.GetConfig().Windows.GapsOut.WithDefaults()
+	if p.state.GetScreenShare() {
+		return gaps.Share
+	}
+	return gaps.Normal
+}
+
+func (p *PiP) config() config.PiPConfig {
+	return p.state.GetConfig().Windows.PiP.WithDefaults()
+}
+
+func (p *PiP) window(addr string) (*hypr.Window, error) {
+	clients, err := p.hypr.Clients()
+	if err != nil {
+		return nil, fmt.Errorf("get clients: %w", err)
+	}
+	for i := range clients {
+		if clients[i].Address == addr {
+			return &clients[i], nil
+		}
+	}
+	return nil, nil
+}
@@ -0,0 +1,170 @@
+package wm
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"dotfiles/cmds/internal/hyprd/hypr"
+	"dotfiles/cmds/internal/hyprd/state"
+	"dotfiles/cmds/internal/hyprd/windows"
+)
+
+const (
+	swallowSettle   = 100 * time.Millisecond // let the child's tile land before reading geometry
+	swallowMaxDepth = 32                     // /proc parent hops before giving up
+)
+
+// Swallow hides a terminal while a window launched from it is open and restores the
+// terminal to the same tile slot when that window closes.
+//
+// The parent is parked with the Hide machinery, so `hyprd hide` on the hidden workspace
+// still brings it back early.
+type Swallow struct {
+	hypr      *hypr.Client
+	state     *state.State
+	ancestors func(pid int) map[int]bool
+}
+
+// NewSwallow returns a Swallow that finds a window's parent processes with ancestors; nil
+// uses ProcessAncestors. Replay passes the chains recorded with its trace instead.
+func NewSwallow(h *hypr.Client, s *state.State, ancestors func(pid int) map[int]bool) *Swallow {
+	if ancestors == nil {
+		ancestors = ProcessAncestors
+	}
+	return &Swallow{hypr: h, state: s, ancestors: ancestors}
+}
+
+// Opened swallows the terminal that launched the window at addr, if windows.swallow allows it.
+// It returns "" when nothing was swallowed.
+func (s *Swallow) Opened(addr string) (string, error) {
+	cfg := s.state.GetConfig().Windows.Swallow.WithDefaults()
+	if !cfg.Enabled {
+		return "", nil
+	}
+	time.Sleep(swallowSettle)
+
+	clients, err := s.hypr.Clients()
+	if err != nil {
+		return "", err
+	}
+	var child *hypr.Window
+	for i := range clients {
+		if clients[i].Address == addr {
+			child = &clients[i]
+		}
+	}
+	if child == nil || child.Floating || child.Pid <= 0 || !cfg.Swallows(child.Class) {
+		return "", nil
+	}
+	ws := child.Workspace.ID
+	if s.state.GetThreeBody(ws) != nil || s.state.GetMonocle(ws) != nil {
+		return "", nil
+	}
+
+	ancestors := s.ancestors(child.Pid)
+	var parent *hypr.Window
+	for i, c := range clients {
+		if c.Workspace.ID != ws || c.Floating || !cfg.IsTerminal(c.Class) || !ancestors[c.Pid] {
+			continue
+		}
+		// A single-instance terminal owns several windows; prefer the most recently focused.
+		if parent == nil || c.FocusHistoryID < parent.FocusHistoryID {
+			parent = &clients[i]
+		}
+	}
+	if parent == nil {
+		return "", nil
+	}
+
+	tiled, err := windows.GetTiledWindows(s.hypr, ws)
+	if err != nil {
+		return "", err
+	}
+	var others []hypr.Window
+	for _, w := range tiled {
+		if w.Address != child.Address {
+			others = append(others, w)
+		}
+	}
+	hidden := &state.HiddenState{
+		Address:     parent.Address,
+		OriginWS:    ws,
+		SlaveIndex:  max(windows.SlaveIndex(windows.GetSlaves(others), parent.Address), 0),
+		WasMaster:   windows.IsMaster(others, parent.Address),
+		SwallowedBy: child.Address,
+	}
+	s.state.AddHidden(hidden)
+	if err := s.hypr.MoveWindowToWorkspace(parent.Address, windows.HiddenWorkspace, false); err != nil {
+		s.state.RemoveHidden(parent.Address)
+		return "", fmt.Errorf("swallow %s: %w", parent.Address, err)
+	}
+
+	// Walk the child into the slot the terminal left.
+	if err := s.hypr.FocusWindow(child.Address); err == nil {
+		s.restoreSlot(hidden)
+	}
+	return fmt.Sprintf("swallowed: %s by %s (%s)", parent.Address, child.Address, child.Class), nil
+}
+
+// Closed restores the terminal swallowed by the window at addr. It returns "" when addr
+// had not swallowed anything.
+func (s *Swallow) Closed(addr string) (string, error) {
+	hidden := s.state.TakeSwallowed(addr)
+	if hidden == nil {
+		return "", nil
+	}
+	follow := s.state.GetWorkspace() == hidden.OriginWS
+	if err := s.hypr.MoveWindowToWorkspace(hidden.Address, strconv.Itoa(hidden.OriginWS), follow); err != nil {
+		return "", fmt.Errorf("restore swallowed %s: %w", hidden.Address, err)
+	}
+	// Slot moves act on the focused window, so only walk it back while its workspace is shown.
+	if follow {
+		s.restoreSlot(hidden)
+	}
+	return fmt.Sprintf("restored: %s to ws%d", hidden.Address, hidden.OriginWS), nil
+}
+
+// restoreSlot moves the focused window, appended at the tail, into hidden's master or slave slot.
+func (s *Swallow) restoreSlot(hidden *state.HiddenState) {
+	if hidden.WasMaster {
+		_ = s.hypr.LayoutMsg("swapwithmaster master")
+		return
+	}
+	NewHide(s.hypr, s.state).restoreSlavePosition(hidden.OriginWS, hidden.SlaveIndex)
+}
+
+// ProcessAncestors returns pid's parent chain from /proc, excluding pid itself.
+func ProcessAncestors(pid int) map[int]bool {
+	ancestors := make(map[int]bool)
+	for range swallowMaxDepth {
+		ppid, err := parentPID(pid)
+		if err != nil || ppid <= 1 || ancestors[ppid] {
+			break
+		}
+		ancestors[ppid] = true
+		pid = ppid
+	}
+	return ancestors
+}
+
+// parentPID reads field 4 of /proc/<pid>/stat. The command name in field 2 may contain
+// spaces or parentheses, so fields are counted from its closing parenthesis.
+func parentPID(pid int) (int, error) {
+	data, err := os.ReadFile(fmt.Sprintf("/proc/%d/stat", pid))
+	if err != nil {
+		return 0, err
+	}
+	stat := string(data)
+	end := strings.LastIndexByte(stat, ')')
+	if end < 0 {
+		return 0, fmt.Errorf("parse /proc/%d/stat", pid)
+	}
+	fields := strings.Fields(stat[end+1:])
+	if len(fields) < 2 {
+		return 0, fmt.Errorf("parse /proc/%d/stat", pid)
+	}
+	return strconv.Atoi(fields[1])
+}
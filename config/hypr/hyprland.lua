@@ -203,6 +203,8 @@ hl.workspace_rule({ workspace = "special:stash", gaps_out = 120 })
 
 require("binds")
 
+local theme = require("theme") -- rendered by `dctl theme apply`
+
 -- ╭───────────────────────────────────────────────────────────────────────────────╮
 -- │                                    layout                                     │
 -- ╰───────────────────────────────────────────────────────────────────────────────╯
@@ -213,8 +215,8 @@ hl.config({
 		gaps_in = 10,
 		gaps_out = { top = 85, right = 86, bottom = 30, left = 126 }, -- top, right, bottom, left — room for eww bar
 		col = {
-			active_border = "rgb(f2a170)",
-			inactive_border = "rgb(7492ef)",
+			active_border = theme.active_border,
+			inactive_border = theme.inactive_border,
 		},
 		layout = "master",
 		resize_on_border = false,
@@ -228,8 +230,8 @@ hl.config({
 			enabled = true,
 			range = 24,
 			render_power = 4,
-			color = "rgba(e56b2c08)",
-			color_inactive = "rgba(4a6be305)",
+			color = theme.shadow,
+			color_inactive = theme.shadow_inactive,
 			offset = { 0, 0 },
 		},
 	},
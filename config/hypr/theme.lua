@@ -0,0 +1,9 @@
+-- Generated by `dctl theme apply` (vagari) from cmds/config/theme/hypr.lua.tmpl.
+-- hyprd repaints the active border at runtime from the same palette's accent role.
+
+return {
+	active_border = "rgb(f2a170)",
+	inactive_border = "rgb(7492ef)",
+	shadow = "rgba(e56b2c08)",
+	shadow_inactive = "rgba(4a6be305)",
+}